@@ -0,0 +1,308 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FORMAT_TEXT  = "text"
+	FORMAT_JSON  = "json"
+	FORMAT_YAML  = "yaml"
+	FORMAT_TABLE = "table"
+)
+
+var ErrUnknownFormat = errors.New("The output format must be text, json, yaml or table")
+
+// The field names of these types are part of the CLI contract: scripts read
+// them from the json and yaml formats, so they must not be renamed.
+type Product struct {
+	Id       string `json:"id" yaml:"id"`
+	Name     string `json:"name" yaml:"name"`
+	Price    Price  `json:"price" yaml:"price"`
+	Currency string `json:"currency" yaml:"currency"`
+	Status   string `json:"status" yaml:"status"`
+	Version  int    `json:"version" yaml:"version"`
+}
+
+type ProductList struct {
+	Products   []Product `json:"products" yaml:"products"`
+	NextCursor string    `json:"next_cursor,omitempty" yaml:"next_cursor,omitempty"`
+}
+
+type ProductChange struct {
+	Version   int       `json:"version" yaml:"version"`
+	Field     string    `json:"field" yaml:"field"`
+	OldValue  *string   `json:"old_value" yaml:"old_value"`
+	NewValue  string    `json:"new_value" yaml:"new_value"`
+	Actor     string    `json:"actor" yaml:"actor"`
+	ChangedAt time.Time `json:"changed_at" yaml:"changed_at"`
+}
+
+type PurgedProduct struct {
+	Id     string `json:"id" yaml:"id"`
+	Purged bool   `json:"purged" yaml:"purged"`
+}
+
+type BatchReport struct {
+	Mode      string      `json:"mode" yaml:"mode"`
+	Succeeded int         `json:"succeeded" yaml:"succeeded"`
+	Failed    int         `json:"failed" yaml:"failed"`
+	Results   []BatchItem `json:"results" yaml:"results"`
+}
+
+type BatchItem struct {
+	Item    int    `json:"item" yaml:"item"`
+	Id      string `json:"id" yaml:"id"`
+	Outcome string `json:"outcome" yaml:"outcome"`
+	Error   *Error `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun    bool        `json:"dry_run" yaml:"dry_run"`
+	Succeeded int         `json:"succeeded" yaml:"succeeded"`
+	Failed    int         `json:"failed" yaml:"failed"`
+	Rows      []ImportRow `json:"rows" yaml:"rows"`
+}
+
+type ImportRow struct {
+	Line    int    `json:"line" yaml:"line"`
+	Id      string `json:"id" yaml:"id"`
+	Outcome string `json:"outcome" yaml:"outcome"`
+	Error   *Error `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type Error struct {
+	Code    string       `json:"code" yaml:"code"`
+	Message string       `json:"message" yaml:"message"`
+	Fields  []FieldError `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field" yaml:"field"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// Price keeps the exact decimal amount and is written as a number in both
+// json and yaml.
+type Price string
+
+func (p Price) MarshalJSON() ([]byte, error) {
+	return []byte(p), nil
+}
+
+func (p Price) MarshalYAML() (any, error) {
+	tag := "!!int"
+	if strings.Contains(string(p), ".") {
+		tag = "!!float"
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: string(p)}, nil
+}
+
+func ValidFormat(format string) bool {
+	switch format {
+	case FORMAT_TEXT, FORMAT_JSON, FORMAT_YAML, FORMAT_TABLE:
+		return true
+	}
+	return false
+}
+
+func newProduct(product application.ProductInterface) Product {
+	return Product{
+		Id:       product.GetId(),
+		Name:     product.GetName(),
+		Price:    Price(product.GetPrice().Decimal()),
+		Currency: product.GetPrice().Currency,
+		Status:   product.GetStatus(),
+		Version:  product.GetVersion(),
+	}
+}
+
+func NewError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	result := &Error{Message: err.Error()}
+	var validationErr *application.ValidationError
+	var pathErr *fs.PathError
+	switch {
+	case errors.Is(err, application.ErrProductNotFound):
+		result.Code = "NOT_FOUND"
+	case errors.Is(err, application.ErrConcurrentModification):
+		result.Code = "CONCURRENT_MODIFICATION"
+	case errors.Is(err, application.ErrInvalidTransition):
+		result.Code = "INVALID_TRANSITION"
+	case errors.Is(err, application.ErrBatchAborted):
+		result.Code = "BATCH_ABORTED"
+	case errors.As(err, &validationErr):
+		result.Code = "VALIDATION_FAILED"
+		for _, field := range validationErr.Fields {
+			result.Fields = append(result.Fields, FieldError{Field: field.Field, Message: field.Message})
+		}
+	case errors.Is(err, application.ErrInvalidPrice):
+		result.Code = "VALIDATION_FAILED"
+	case errors.As(err, &pathErr):
+		result.Code = "FILE_ERROR"
+	default:
+		result.Code = "STORAGE_ERROR"
+	}
+	return result
+}
+
+// FormatError renders err as an {"error": ...} object in the json and yaml
+// formats and as its message otherwise.
+func FormatError(err error, format string) string {
+	if format != FORMAT_JSON && format != FORMAT_YAML {
+		return err.Error()
+	}
+	result, marshalErr := marshal(format, struct {
+		Error *Error `json:"error" yaml:"error"`
+	}{NewError(err)})
+	if marshalErr != nil {
+		return err.Error()
+	}
+	return result
+}
+
+// output is a result that can also be shown to people, as sentences or as a
+// table.
+type output interface {
+	text() string
+	table() string
+}
+
+func render(format string, result output) (string, error) {
+	switch format {
+	case FORMAT_TEXT:
+		return result.text(), nil
+	case FORMAT_TABLE:
+		return result.table(), nil
+	case FORMAT_JSON, FORMAT_YAML:
+		return marshal(format, result)
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+func marshal(format string, value any) (string, error) {
+	if format == FORMAT_YAML {
+		result, err := yaml.Marshal(value)
+		return strings.TrimSuffix(string(result), "\n"), err
+	}
+	result, err := json.MarshalIndent(value, "", "  ")
+	return string(result), err
+}
+
+func table(header string, rows []string) string {
+	var builder strings.Builder
+	writer := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, header)
+	for _, row := range rows {
+		fmt.Fprintln(writer, row)
+	}
+	writer.Flush()
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+type productResult struct {
+	Product `yaml:",inline"`
+	message string
+}
+
+func (r productResult) text() string {
+	return r.message
+}
+
+func (r productResult) table() string {
+	return table("ID\tNAME\tPRICE\tSTATUS\tVERSION", []string{r.row() + fmt.Sprintf("\t%d", r.Version)})
+}
+
+func (p Product) row() string {
+	return fmt.Sprintf("%s\t%s\t%s %s\t%s", p.Id, p.Name, p.Price, p.Currency, p.Status)
+}
+
+func (l ProductList) text() string {
+	return l.table()
+}
+
+func (l ProductList) table() string {
+	var rows []string
+	for _, product := range l.Products {
+		rows = append(rows, product.row())
+	}
+	result := table("ID\tNAME\tPRICE\tSTATUS", rows)
+	if l.NextCursor != "" {
+		result += "\nNext cursor: " + l.NextCursor
+	}
+	return result
+}
+
+type productChanges []ProductChange
+
+func (c productChanges) text() string {
+	return c.table()
+}
+
+func (c productChanges) table() string {
+	var rows []string
+	for _, change := range c {
+		old := "-"
+		if change.OldValue != nil {
+			old = *change.OldValue
+		}
+		rows = append(rows, fmt.Sprintf("%d\t%s\t%s\t%s\t%s\t%s", change.Version, change.ChangedAt.Format(time.RFC3339), change.Actor, change.Field, old, change.NewValue))
+	}
+	return table("VERSION\tCHANGED AT\tACTOR\tFIELD\tOLD\tNEW", rows)
+}
+
+func (p PurgedProduct) text() string {
+	return fmt.Sprintf("Product %s has been permanently deleted", p.Id)
+}
+
+func (p PurgedProduct) table() string {
+	return table("ID\tPURGED", []string{fmt.Sprintf("%s\t%t", p.Id, p.Purged)})
+}
+
+func (e *Error) reason() string {
+	if e == nil {
+		return ""
+	}
+	return e.Message
+}
+
+func (r BatchReport) text() string {
+	return r.table() + fmt.Sprintf("\n%d succeeded, %d failed (%s)", r.Succeeded, r.Failed, r.Mode)
+}
+
+func (r BatchReport) table() string {
+	var rows []string
+	for _, result := range r.Results {
+		rows = append(rows, fmt.Sprintf("%d\t%s\t%s\t%s", result.Item, result.Id, result.Outcome, result.Error.reason()))
+	}
+	return table("ITEM\tID\tOUTCOME\tREASON", rows)
+}
+
+func (r ImportReport) text() string {
+	result := r.table() + fmt.Sprintf("\n%d rows succeeded, %d failed", r.Succeeded, r.Failed)
+	if r.DryRun {
+		result += " (dry run, nothing was saved)"
+	}
+	return result
+}
+
+func (r ImportReport) table() string {
+	var rows []string
+	for _, row := range r.Rows {
+		rows = append(rows, fmt.Sprintf("%d\t%s\t%s\t%s", row.Line, row.Id, row.Outcome, row.Error.reason()))
+	}
+	return table("LINE\tID\tOUTCOME\tREASON", rows)
+}
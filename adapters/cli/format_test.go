@@ -0,0 +1,135 @@
+package cli_test
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/cli"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/memory"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormats(t *testing.T) {
+	ctx := context.Background()
+	service := application.NewProductService(memory.NewProductMemory())
+	product, err := service.Create(ctx, "Product 1", application.NewMoney(1999, application.DEFAULT_CURRENCY))
+	assert.Nil(t, err)
+	id := product.GetId()
+
+	t.Run("Success - JSON", func(t *testing.T) {
+		result, err := cli.Run(ctx, service, "get", id, "", application.Money{}, cli.FORMAT_JSON)
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"id":"`+id+`","name":"Product 1","price":19.99,"currency":"BRL","status":"disabled","version":1}`, result)
+
+		result, err = cli.List(ctx, service, application.ProductQuery{Status: application.ENABLED}, cli.FORMAT_JSON)
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"products":[]}`, result)
+	})
+
+	t.Run("Success - YAML", func(t *testing.T) {
+		result, err := cli.List(ctx, service, application.ProductQuery{}, cli.FORMAT_YAML)
+		assert.Nil(t, err)
+		assert.Equal(t, "products:\n    - id: "+id+"\n      name: Product 1\n      price: 19.99\n      currency: BRL\n      status: disabled\n      version: 1", result)
+	})
+
+	t.Run("Success - Table", func(t *testing.T) {
+		result, err := cli.Run(ctx, service, "get", id, "", application.Money{}, cli.FORMAT_TABLE)
+		assert.Nil(t, err)
+		lines := strings.Split(result, "\n")
+		assert.Len(t, lines, 2)
+		assert.Regexp(t, `^ID +NAME +PRICE +STATUS +VERSION$`, lines[0])
+		assert.Regexp(t, `^`+id+` +Product 1 +19\.99 BRL +disabled +1$`, lines[1])
+	})
+
+	t.Run("Success - Purge", func(t *testing.T) {
+		other, err := service.Create(ctx, "Product 2", application.NewMoney(0, application.DEFAULT_CURRENCY))
+		assert.Nil(t, err)
+		result, err := cli.Run(ctx, service, "purge", other.GetId(), "", application.Money{}, cli.FORMAT_JSON)
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"id":"`+other.GetId()+`","purged":true}`, result)
+	})
+
+	t.Run("Error - Unknown format", func(t *testing.T) {
+		_, err := cli.Run(ctx, service, "get", id, "", application.Money{}, "xml")
+		assert.ErrorIs(t, err, cli.ErrUnknownFormat)
+		assert.False(t, cli.ValidFormat("xml"))
+
+		_, err = cli.Run(ctx, service, "create", "", "Product 3", application.NewMoney(100, application.DEFAULT_CURRENCY), "xml")
+		assert.ErrorIs(t, err, cli.ErrUnknownFormat)
+		page, err := service.List(ctx, application.ProductQuery{Name: "Product 3"})
+		assert.Nil(t, err)
+		assert.Empty(t, page.Products)
+
+		_, err = cli.Import(ctx, service, strings.NewReader("id,name,price,status\n,Product 3,1,disabled\n"), false, "xml")
+		assert.ErrorIs(t, err, cli.ErrUnknownFormat)
+		page, err = service.List(ctx, application.ProductQuery{Name: "Product 3"})
+		assert.Nil(t, err)
+		assert.Empty(t, page.Products)
+	})
+}
+
+func TestFormatsBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	serviceMock := mock.NewMockProductServiceInterface(ctrl)
+	serviceMock.EXPECT().EnableMany(gomock.Any(), []string{"1", "2"}, application.BATCH_BEST_EFFORT).Return(application.BatchReport{
+		Mode: application.BATCH_BEST_EFFORT,
+		Results: []application.BatchItemResult{
+			{Index: 0, Id: "1", Outcome: application.OUTCOME_ENABLED},
+			{Index: 1, Id: "2", Outcome: application.OUTCOME_FAILED, Err: application.ErrProductNotFound},
+		},
+	}, nil).Times(1)
+
+	result, err := cli.RunBatch(context.Background(), serviceMock, "enable-many", []string{"1", "2"}, nil, application.BATCH_BEST_EFFORT, cli.FORMAT_JSON)
+	assert.ErrorIs(t, err, application.ErrProductNotFound)
+	assert.JSONEq(t, `{"mode":"best-effort","succeeded":1,"failed":1,"results":[
+		{"item":1,"id":"1","outcome":"enabled"},
+		{"item":2,"id":"2","outcome":"failed","error":{"code":"NOT_FOUND","message":"Product not found"}}
+	]}`, result)
+}
+
+func TestFormatError(t *testing.T) {
+	tests := []struct {
+		testName string
+		err      error
+		format   string
+		expected string
+	}{
+		{
+			testName: "Success - Text",
+			err:      application.ErrProductNotFound,
+			format:   cli.FORMAT_TEXT,
+			expected: "Product not found",
+		},
+		{
+			testName: "Success - JSON",
+			err:      application.ErrConcurrentModification,
+			format:   cli.FORMAT_JSON,
+			expected: "{\n  \"error\": {\n    \"code\": \"CONCURRENT_MODIFICATION\",\n    \"message\": \"The product was modified by another operation\"\n  }\n}",
+		},
+		{
+			testName: "Success - YAML with fields",
+			err:      application.NewValidationError("name", "The name is required", nil),
+			format:   cli.FORMAT_YAML,
+			expected: "error:\n    code: VALIDATION_FAILED\n    message: The name is required\n    fields:\n        - field: name\n          message: The name is required",
+		},
+		{
+			testName: "Success - File error",
+			err:      &fs.PathError{Op: "open", Path: "products.csv", Err: fs.ErrNotExist},
+			format:   cli.FORMAT_JSON,
+			expected: "{\n  \"error\": {\n    \"code\": \"FILE_ERROR\",\n    \"message\": \"open products.csv: file does not exist\"\n  }\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			assert.Equal(t, tt.expected, cli.FormatError(tt.err, tt.format))
+		})
+	}
+}
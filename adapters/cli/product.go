@@ -1,48 +1,209 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 
 	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
 )
 
-func Run(service application.ProductServiceInterface, action, productId, producName string, productPrice float64) (string, error) {
+func Run(ctx context.Context, service application.ProductServiceInterface, action, productId, producName string, productPrice application.Money, format string) (string, error) {
+	if !ValidFormat(format) {
+		return "", ErrUnknownFormat
+	}
+	var product application.ProductInterface
+	var err error
 	result := ""
 
 	switch action {
 	case "create":
-		product, err := service.Create(producName, productPrice)
+		product, err = service.Create(ctx, producName, productPrice)
 		if err != nil {
 			return result, err
 		}
-		result = fmt.Sprintf("Product Id %s with the name %s has been created with the price %f and status %s", product.GetId(), product.GetName(), product.GetPrice(), product.GetStatus())
+		result = fmt.Sprintf("Product Id %s with the name %s has been created with the price %s and status %s", product.GetId(), product.GetName(), product.GetPrice(), product.GetStatus())
 	case "enable":
-		product, err := service.Get(productId)
+		product, err = service.Get(ctx, productId)
 		if err != nil {
 			return result, err
 		}
-		product, err = service.Enable(product)
+		product, err = service.Enable(ctx, product)
 		if err != nil {
 			return result, err
 		}
 		result = fmt.Sprintf("Product %s has been enabled", product.GetName())
 	case "disable":
-		product, err := service.Get(productId)
+		product, err = service.Get(ctx, productId)
 		if err != nil {
 			return result, err
 		}
-		product, err = service.Disable(product)
+		product, err = service.Disable(ctx, product)
 		if err != nil {
 			return result, err
 		}
 		result = fmt.Sprintf("Product %s has been enabled", product.GetName())
+	case "rename":
+		product, err = service.Get(ctx, productId)
+		if err != nil {
+			return result, err
+		}
+		previousName := product.GetName()
+		product, err = service.ChangeName(ctx, product, producName)
+		if err != nil {
+			return result, err
+		}
+		result = fmt.Sprintf("Product %s has been renamed to %s", previousName, product.GetName())
+	case "set-price":
+		product, err = service.Get(ctx, productId)
+		if err != nil {
+			return result, err
+		}
+		product, err = service.ChangePrice(ctx, product, productPrice)
+		if err != nil {
+			return result, err
+		}
+		result = fmt.Sprintf("Product %s price has been changed to %s", product.GetName(), product.GetPrice())
+	case "archive":
+		product, err = service.Get(ctx, productId)
+		if err != nil {
+			return result, err
+		}
+		product, err = service.Archive(ctx, product)
+		if err != nil {
+			return result, err
+		}
+		result = fmt.Sprintf("Product %s has been archived", product.GetName())
+	case "restore":
+		product, err = service.Restore(ctx, productId)
+		if err != nil {
+			return result, err
+		}
+		result = fmt.Sprintf("Product %s has been restored", product.GetName())
+	case "purge":
+		if err := service.Purge(ctx, productId); err != nil {
+			return result, err
+		}
+		return render(format, PurgedProduct{Id: productId, Purged: true})
+	case "list":
+		return List(ctx, service, application.ProductQuery{}, format)
+	case "history":
+		return History(ctx, service, productId, format)
 	default:
-		product, err := service.Get(productId)
+		if productId == "" {
+			return List(ctx, service, application.ProductQuery{}, format)
+		}
+		product, err = service.Get(ctx, productId)
 		if err != nil {
 			return result, err
 		}
-		result = fmt.Sprintf("Product Id: %s\nName: %s\nPrice: %f\nStatus: %s", product.GetId(), product.GetName(), product.GetPrice(), product.GetStatus())
+		result = fmt.Sprintf("Product Id: %s\nName: %s\nPrice: %s\nStatus: %s", product.GetId(), product.GetName(), product.GetPrice(), product.GetStatus())
+	}
+
+	return render(format, productResult{Product: newProduct(product), message: result})
+}
+
+func List(ctx context.Context, service application.ProductServiceInterface, query application.ProductQuery, format string) (string, error) {
+	if !ValidFormat(format) {
+		return "", ErrUnknownFormat
+	}
+	page, err := service.List(ctx, query)
+	if err != nil {
+		return "", err
+	}
+
+	list := ProductList{Products: []Product{}, NextCursor: page.NextCursor}
+	for _, product := range page.Products {
+		list.Products = append(list.Products, newProduct(product))
+	}
+	return render(format, list)
+}
+
+func History(ctx context.Context, service application.ProductServiceInterface, productId string, format string) (string, error) {
+	if !ValidFormat(format) {
+		return "", ErrUnknownFormat
+	}
+	changes, err := service.History(ctx, productId)
+	if err != nil {
+		return "", err
+	}
+
+	result := productChanges{}
+	for _, change := range changes {
+		result = append(result, ProductChange{
+			Version:   change.Version,
+			Field:     change.Field,
+			OldValue:  change.OldValue,
+			NewValue:  change.NewValue,
+			Actor:     change.Actor,
+			ChangedAt: change.ChangedAt,
+		})
+	}
+	return render(format, result)
+}
+
+// The report is rendered even when items fail; the returned error carries the
+// first failure so callers can tell why the batch did not fully succeed.
+func RunBatch(ctx context.Context, service application.ProductServiceInterface, action string, ids []string, inputs []application.ProductInput, mode string, format string) (string, error) {
+	if !ValidFormat(format) {
+		return "", ErrUnknownFormat
+	}
+	var report application.BatchReport
+	var err error
+	switch action {
+	case "create-many":
+		report, err = service.CreateMany(ctx, inputs, mode)
+	case "enable-many":
+		report, err = service.EnableMany(ctx, ids, mode)
+	case "disable-many":
+		report, err = service.DisableMany(ctx, ids, mode)
+	default:
+		return "", fmt.Errorf("unknown batch action %q", action)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	output := BatchReport{Mode: report.Mode, Succeeded: report.Succeeded(), Failed: report.Failed(), Results: []BatchItem{}}
+	for _, result := range report.Results {
+		output.Results = append(output.Results, BatchItem{Item: result.Index + 1, Id: result.Id, Outcome: result.Outcome, Error: NewError(result.Err)})
+	}
+	rendered, err := render(format, output)
+	if err != nil {
+		return "", err
+	}
+
+	for _, result := range report.Results {
+		if result.Err != nil && !errors.Is(result.Err, application.ErrBatchAborted) {
+			return rendered, fmt.Errorf("item %d: %w", result.Index+1, result.Err)
+		}
 	}
+	return rendered, nil
+}
 
-	return result, nil
+func Import(ctx context.Context, service application.ProductServiceInterface, r io.Reader, dryRun bool, format string) (string, error) {
+	if !ValidFormat(format) {
+		return "", ErrUnknownFormat
+	}
+	report, err := service.Import(ctx, r, dryRun)
+	if err != nil {
+		return "", err
+	}
+
+	output := ImportReport{DryRun: report.DryRun, Succeeded: len(report.Rows) - report.Failed(), Failed: report.Failed(), Rows: []ImportRow{}}
+	for _, row := range report.Rows {
+		output.Rows = append(output.Rows, ImportRow{Line: row.Line, Id: row.Id, Outcome: row.Outcome, Error: NewError(row.Err)})
+	}
+	rendered, err := render(format, output)
+	if err != nil {
+		return "", err
+	}
+
+	for _, row := range report.Rows {
+		if row.Err != nil && !errors.Is(row.Err, application.ErrBatchAborted) {
+			return rendered, fmt.Errorf("line %d: %w", row.Line, row.Err)
+		}
+	}
+	return rendered, nil
 }
@@ -1,11 +1,16 @@
 package cli_test
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/cli"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/memory"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
 	"github.com/sousapedro11/fc-arquitetura-hexagonal/application/mock"
 	"github.com/stretchr/testify/assert"
 )
@@ -15,7 +20,7 @@ func TestRun(t *testing.T) {
 	defer ctrl.Finish()
 
 	productName := "Product 1"
-	productPrice := 19.99
+	productPrice := application.NewMoney(1999, application.DEFAULT_CURRENCY)
 	productStatus := "enabled"
 	productId := "681051e4-2936-4b4c-87a4-efaf7b8c02ba"
 
@@ -24,15 +29,27 @@ func TestRun(t *testing.T) {
 	productMock.EXPECT().GetName().Return(productName).AnyTimes()
 	productMock.EXPECT().GetPrice().Return(productPrice).AnyTimes()
 	productMock.EXPECT().GetStatus().Return(productStatus).AnyTimes()
+	productMock.EXPECT().GetVersion().Return(2).AnyTimes()
 
 	serviceMock := mock.NewMockProductServiceInterface(ctrl)
-	serviceMock.EXPECT().Create(productName, productPrice).Return(productMock, nil).AnyTimes()
-	serviceMock.EXPECT().Get(productId).Return(productMock, nil).AnyTimes()
-	serviceMock.EXPECT().Enable(productMock).Return(productMock, nil).AnyTimes()
-	serviceMock.EXPECT().Disable(productMock).Return(productMock, nil).AnyTimes()
+	serviceMock.EXPECT().Create(gomock.Any(), productName, productPrice).Return(productMock, nil).AnyTimes()
+	serviceMock.EXPECT().Get(gomock.Any(), productId).Return(productMock, nil).AnyTimes()
+	serviceMock.EXPECT().Enable(gomock.Any(), productMock).Return(productMock, nil).AnyTimes()
+	serviceMock.EXPECT().Disable(gomock.Any(), productMock).Return(productMock, nil).AnyTimes()
+	serviceMock.EXPECT().ChangeName(gomock.Any(), productMock, "Product 2").Return(productMock, nil).AnyTimes()
+	serviceMock.EXPECT().ChangePrice(gomock.Any(), productMock, productPrice).Return(productMock, nil).AnyTimes()
+	serviceMock.EXPECT().Archive(gomock.Any(), productMock).Return(productMock, nil).AnyTimes()
+	serviceMock.EXPECT().Restore(gomock.Any(), productId).Return(productMock, nil).AnyTimes()
+	serviceMock.EXPECT().Purge(gomock.Any(), productId).Return(nil).AnyTimes()
+	serviceMock.EXPECT().List(gomock.Any(), application.ProductQuery{}).Return(application.ProductPage{
+		Products:   []application.ProductInterface{productMock},
+		NextCursor: "next",
+	}, nil).AnyTimes()
+
+	productList := fmt.Sprintf("ID                                    NAME       PRICE      STATUS\n%s  %s  %s  %s\nNext cursor: next", productId, productName, productPrice, productStatus)
 
 	tests := []struct {
-		price    float64
+		price    application.Money
 		expected string
 		testName string
 		name     string
@@ -49,11 +66,11 @@ func TestRun(t *testing.T) {
 			status:   "",
 			action:   "create",
 			err:      false,
-			expected: fmt.Sprintf("Product Id %s with the name %s has been created with the price %f and status %s", productId, productName, productPrice, productStatus),
+			expected: fmt.Sprintf("Product Id %s with the name %s has been created with the price %s and status %s", productId, productName, productPrice, productStatus),
 		},
 		{
 			testName: "Success - Enable",
-			price:    0,
+			price:    application.Money{},
 			name:     "",
 			id:       productId,
 			status:   "",
@@ -63,7 +80,7 @@ func TestRun(t *testing.T) {
 		},
 		{
 			testName: "Success - Disable",
-			price:    0,
+			price:    application.Money{},
 			name:     "",
 			id:       productId,
 			status:   "",
@@ -73,21 +90,180 @@ func TestRun(t *testing.T) {
 		},
 		{
 			testName: "Success - Get",
-			price:    0,
+			price:    application.Money{},
 			name:     "",
 			id:       productId,
 			status:   "",
 			action:   "get",
 			err:      false,
-			expected: fmt.Sprintf("Product Id: %s\nName: %s\nPrice: %f\nStatus: %s", productId, productName, productPrice, productStatus),
+			expected: fmt.Sprintf("Product Id: %s\nName: %s\nPrice: %s\nStatus: %s", productId, productName, productPrice, productStatus),
+		},
+		{
+			testName: "Success - Rename",
+			price:    application.Money{},
+			name:     "Product 2",
+			id:       productId,
+			status:   "",
+			action:   "rename",
+			err:      false,
+			expected: fmt.Sprintf("Product %s has been renamed to %s", productName, productName),
+		},
+		{
+			testName: "Success - Set price",
+			price:    productPrice,
+			name:     "",
+			id:       productId,
+			status:   "",
+			action:   "set-price",
+			err:      false,
+			expected: fmt.Sprintf("Product %s price has been changed to %s", productName, productPrice),
+		},
+		{
+			testName: "Success - Archive",
+			price:    application.Money{},
+			name:     "",
+			id:       productId,
+			status:   "",
+			action:   "archive",
+			err:      false,
+			expected: fmt.Sprintf("Product %s has been archived", productName),
+		},
+		{
+			testName: "Success - Restore",
+			price:    application.Money{},
+			name:     "",
+			id:       productId,
+			status:   "",
+			action:   "restore",
+			err:      false,
+			expected: fmt.Sprintf("Product %s has been restored", productName),
+		},
+		{
+			testName: "Success - Purge",
+			price:    application.Money{},
+			name:     "",
+			id:       productId,
+			status:   "",
+			action:   "purge",
+			err:      false,
+			expected: fmt.Sprintf("Product %s has been permanently deleted", productId),
+		},
+		{
+			testName: "Success - List",
+			price:    application.Money{},
+			name:     "",
+			id:       "",
+			status:   "",
+			action:   "list",
+			err:      false,
+			expected: productList,
+		},
+		{
+			testName: "Success - List without an id",
+			price:    application.Money{},
+			name:     "",
+			id:       "",
+			status:   "",
+			action:   "get",
+			err:      false,
+			expected: productList,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.testName, func(t *testing.T) {
-			result, err := cli.Run(serviceMock, tt.action, tt.id, tt.name, tt.price)
+			result, err := cli.Run(context.Background(), serviceMock, tt.action, tt.id, tt.name, tt.price, cli.FORMAT_TEXT)
 			assert.Equal(t, tt.err, err != nil)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
+
+func TestRunBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	serviceMock := mock.NewMockProductServiceInterface(ctrl)
+	serviceMock.EXPECT().EnableMany(gomock.Any(), []string{"1", "2"}, application.BATCH_BEST_EFFORT).Return(application.BatchReport{
+		Mode: application.BATCH_BEST_EFFORT,
+		Results: []application.BatchItemResult{
+			{Index: 0, Id: "1", Outcome: application.OUTCOME_ENABLED},
+			{Index: 1, Id: "2", Outcome: application.OUTCOME_FAILED, Err: application.ErrProductNotFound},
+		},
+	}, nil).Times(1)
+	serviceMock.EXPECT().CreateMany(gomock.Any(), gomock.Any(), "sometimes").Return(application.BatchReport{}, application.NewValidationError("mode", "The batch mode must be all-or-nothing or best-effort", nil)).Times(1)
+
+	result, err := cli.RunBatch(context.Background(), serviceMock, "enable-many", []string{"1", "2"}, nil, application.BATCH_BEST_EFFORT, cli.FORMAT_TEXT)
+	assert.ErrorIs(t, err, application.ErrProductNotFound)
+	assert.Equal(t, "item 2: Product not found", err.Error())
+	assert.Equal(t, "ITEM  ID  OUTCOME  REASON\n1     1   enabled  \n2     2   failed   Product not found\n1 succeeded, 1 failed (best-effort)", result)
+
+	result, err = cli.RunBatch(context.Background(), serviceMock, "create-many", nil, nil, "sometimes", cli.FORMAT_TEXT)
+	assert.Equal(t, "The batch mode must be all-or-nothing or best-effort", err.Error())
+	assert.Empty(t, result)
+}
+
+func TestImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	serviceMock := mock.NewMockProductServiceInterface(ctrl)
+	serviceMock.EXPECT().Import(gomock.Any(), gomock.Any(), true).Return(application.ImportReport{
+		DryRun: true,
+		Rows: []application.ImportRow{
+			{Line: 2, Id: "1", Outcome: application.OUTCOME_CREATED},
+			{Line: 3, Outcome: application.OUTCOME_FAILED, Err: application.NewValidationError("price", "The price \"ten\" is not a valid amount", application.ErrInvalidPrice)},
+		},
+	}, nil).Times(1)
+
+	result, err := cli.Import(context.Background(), serviceMock, strings.NewReader(""), true, cli.FORMAT_TEXT)
+	assert.ErrorIs(t, err, application.ErrInvalidPrice)
+	assert.Equal(t, `line 3: The price "ten" is not a valid amount`, err.Error())
+	assert.Equal(t, "LINE  ID  OUTCOME  REASON\n2     1   created  \n3         failed   The price \"ten\" is not a valid amount\n1 rows succeeded, 1 failed (dry run, nothing was saved)", result)
+}
+
+func TestHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	changedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	oldPrice := "10.00 BRL"
+	serviceMock := mock.NewMockProductServiceInterface(ctrl)
+	serviceMock.EXPECT().History(gomock.Any(), "1").Return([]application.ProductChange{
+		{ProductId: "1", Version: 1, Field: "price", NewValue: "10.00 BRL", Actor: "alice", ChangedAt: changedAt},
+		{ProductId: "1", Version: 2, Field: "price", OldValue: &oldPrice, NewValue: "19.99 BRL", Actor: "bob", ChangedAt: changedAt},
+	}, nil).Times(1)
+	serviceMock.EXPECT().History(gomock.Any(), "missing").Return(nil, application.ErrProductNotFound).Times(1)
+
+	result, err := cli.Run(context.Background(), serviceMock, "history", "1", "", application.Money{}, cli.FORMAT_TEXT)
+	assert.Nil(t, err)
+	assert.Equal(t, "VERSION  CHANGED AT            ACTOR  FIELD  OLD        NEW\n1        2026-01-02T03:04:05Z  alice  price  -          10.00 BRL\n2        2026-01-02T03:04:05Z  bob    price  10.00 BRL  19.99 BRL", result)
+
+	_, err = cli.Run(context.Background(), serviceMock, "history", "missing", "", application.Money{}, cli.FORMAT_TEXT)
+	assert.ErrorIs(t, err, application.ErrProductNotFound)
+}
+
+func TestRunInMemory(t *testing.T) {
+	ctx := application.WithActor(context.Background(), "alice")
+	service := application.NewProductService(memory.NewProductMemory())
+
+	result, err := cli.Run(ctx, service, "create", "", "Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY), cli.FORMAT_TEXT)
+	assert.Nil(t, err)
+	page, err := service.List(ctx, application.ProductQuery{})
+	assert.Nil(t, err)
+	productId := page.Products[0].GetId()
+	assert.Equal(t, fmt.Sprintf("Product Id %s with the name Product 1 has been created with the price 10.00 BRL and status disabled", productId), result)
+
+	result, err = cli.Run(ctx, service, "set-price", productId, "", application.NewMoney(1250, application.DEFAULT_CURRENCY), cli.FORMAT_TEXT)
+	assert.Nil(t, err)
+	assert.Equal(t, "Product Product 1 price has been changed to 12.50 BRL", result)
+
+	result, err = cli.Run(ctx, service, "history", productId, "", application.Money{}, cli.FORMAT_TEXT)
+	assert.Nil(t, err)
+	lines := strings.Split(result, "\n")
+	assert.Len(t, lines, 5)
+	assert.Regexp(t, `^2 .* alice +price +10\.00 BRL +12\.50 BRL$`, lines[4])
+
+	_, err = cli.Run(ctx, service, "enable", "missing", "", application.Money{}, cli.FORMAT_TEXT)
+	assert.ErrorIs(t, err, application.ErrProductNotFound)
+}
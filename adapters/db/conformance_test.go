@@ -0,0 +1,42 @@
+package db_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/db"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application/persistencetest"
+)
+
+// Every connection to ":memory:" opens its own database, so the pool is
+// limited to one.
+func openMemoryDb(t *testing.T) *sql.DB {
+	conn, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetMaxOpenConns(1)
+	t.Cleanup(func() { conn.Close() })
+	if err := db.Migrate(conn); err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func TestProductDbConformance(t *testing.T) {
+	persistencetest.Run(t, func(t *testing.T) application.ProductPersistenceInterface {
+		productDb := db.NewProductDb(openMemoryDb(t))
+		t.Cleanup(func() { productDb.Close() })
+		return productDb
+	})
+}
+
+func TestProductEventStoreConformance(t *testing.T) {
+	persistencetest.Run(t, func(t *testing.T) application.ProductPersistenceInterface {
+		store := db.NewProductEventStore(openMemoryDb(t))
+		store.SnapshotInterval = 2
+		t.Cleanup(func() { store.Close() })
+		return store
+	})
+}
@@ -0,0 +1,443 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+)
+
+const DEFAULT_SNAPSHOT_INTERVAL = 50
+
+// ProductEventStore persists every product as an append-only stream of
+// domain events and rebuilds it by replaying the stream on top of its latest
+// snapshot. It behaves like ProductDb, so either can back the service, and it
+// shares ProductDb's statement cache, transactions and outbox.
+type ProductEventStore struct {
+	store *ProductDb
+	// A snapshot is written once this many events were appended to a stream
+	// since its last one; zero disables snapshots.
+	SnapshotInterval int
+}
+
+func NewProductEventStore(db *sql.DB) *ProductEventStore {
+	return &ProductEventStore{store: NewProductDb(db), SnapshotInterval: DEFAULT_SNAPSHOT_INTERVAL}
+}
+
+func (s *ProductEventStore) WithOutbox() *ProductEventStore {
+	s.store.WithOutbox()
+	return s
+}
+
+func (s *ProductEventStore) Close() error {
+	return s.store.Close()
+}
+
+func (s *ProductEventStore) WithinTransaction(ctx context.Context, fn func(ctx context.Context, persistence application.ProductPersistenceInterface) error) error {
+	return s.transaction(ctx, func(tx *ProductEventStore) error {
+		return fn(ctx, tx)
+	})
+}
+
+func (s *ProductEventStore) transaction(ctx context.Context, fn func(tx *ProductEventStore) error) error {
+	return s.store.transaction(ctx, func(tx *ProductDb) error {
+		return fn(&ProductEventStore{store: tx, SnapshotInterval: s.SnapshotInterval})
+	})
+}
+
+func (s *ProductEventStore) Get(ctx context.Context, id string) (application.ProductInterface, error) {
+	product, _, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil || product.DeletedAt != nil {
+		return nil, fmt.Errorf("%w: %s", application.ErrProductNotFound, id)
+	}
+	return product, nil
+}
+
+func (s *ProductEventStore) GetArchived(ctx context.Context, id string) (application.ProductInterface, error) {
+	product, _, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil || product.DeletedAt == nil {
+		return nil, fmt.Errorf("%w: %s", application.ErrProductNotFound, id)
+	}
+	return product, nil
+}
+
+// List rebuilds every stream before filtering, so it suits catalogs that fit
+// in memory.
+func (s *ProductEventStore) List(ctx context.Context, query application.ProductQuery) (application.ProductPage, error) {
+	if _, err := query.Normalize(); err != nil {
+		return application.ProductPage{}, err
+	}
+
+	rows, err := s.store.conn().QueryContext(ctx, "select product_id from product_streams")
+	if err != nil {
+		return application.ProductPage{}, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return application.ProductPage{}, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return application.ProductPage{}, err
+	}
+
+	var products []application.ProductInterface
+	for _, id := range ids {
+		product, _, err := s.load(ctx, id)
+		if err != nil {
+			return application.ProductPage{}, err
+		}
+		if product != nil {
+			products = append(products, product)
+		}
+	}
+	return query.Apply(products)
+}
+
+// load replays the stream of a product from its latest snapshot. It returns
+// nil when the stream does not exist, along with the number of events
+// replayed after the snapshot.
+func (s *ProductEventStore) load(ctx context.Context, id string) (*application.Product, int, error) {
+	stmt, err := s.store.prepare(ctx, selectStream)
+	if err != nil {
+		return nil, 0, err
+	}
+	var version int
+	err = stmt.QueryRowContext(ctx, id).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var product *application.Product
+	var snapshotId int64
+	stmt, err = s.store.prepare(ctx, selectSnapshot)
+	if err != nil {
+		return nil, 0, err
+	}
+	var payload string
+	err = stmt.QueryRowContext(ctx, id).Scan(&snapshotId, &payload)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, err
+	}
+	if err == nil {
+		product = &application.Product{}
+		if err := json.Unmarshal([]byte(payload), product); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	stmt, err = s.store.prepare(ctx, selectEvents)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err := stmt.QueryContext(ctx, id, snapshotId)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	replayed := 0
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name, &payload); err != nil {
+			return nil, 0, err
+		}
+		event, err := application.UnmarshalEvent(name, []byte(payload))
+		if err != nil {
+			return nil, 0, err
+		}
+		if product, err = apply(product, event); err != nil {
+			return nil, 0, err
+		}
+		replayed++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if product == nil {
+		return nil, 0, fmt.Errorf("stream of product %s has no events", id)
+	}
+
+	product.Version = version
+	return product, replayed, nil
+}
+
+func apply(product *application.Product, event application.DomainEvent) (*application.Product, error) {
+	if created, ok := event.(application.ProductCreated); ok {
+		return &application.Product{Id: created.ProductId, Name: created.Name, Price: created.Price, Status: application.DISABLED}, nil
+	}
+	if product == nil {
+		return nil, fmt.Errorf("stream of product %s does not start with %s", event.GetProductId(), application.PRODUCT_CREATED)
+	}
+
+	switch event := event.(type) {
+	case application.ProductRenamed:
+		product.Name = event.NewName
+	case application.ProductPriceChanged:
+		product.Price = event.NewPrice
+	case application.ProductEnabled:
+		product.Status = application.ENABLED
+	case application.ProductDisabled:
+		product.Status = application.DISABLED
+	case application.ProductArchived:
+		deletedAt := event.OccurredAt
+		product.Status = application.ARCHIVED
+		product.DeletedAt = &deletedAt
+	case application.ProductRestored:
+		product.Status = application.DISABLED
+		product.DeletedAt = nil
+	}
+	return product, nil
+}
+
+// streamEvents describes the difference between the stored product and the
+// one being saved, so the stream stays complete whatever events the caller
+// recorded.
+func streamEvents(previous *application.Product, product application.ProductInterface) []application.DomainEvent {
+	event := application.ProductEvent{ProductId: product.GetId(), OccurredAt: time.Now().UTC()}
+	var events []application.DomainEvent
+
+	status := application.DISABLED
+	if previous == nil {
+		events = append(events, application.ProductCreated{ProductEvent: event, Name: product.GetName(), Price: product.GetPrice()})
+	} else {
+		status = previous.Status
+		if previous.Name != product.GetName() {
+			events = append(events, application.ProductRenamed{ProductEvent: event, OldName: previous.Name, NewName: product.GetName()})
+		}
+		if previous.Price != product.GetPrice() {
+			events = append(events, application.ProductPriceChanged{ProductEvent: event, OldPrice: previous.Price, NewPrice: product.GetPrice()})
+		}
+	}
+
+	if status == application.ARCHIVED && product.GetStatus() != application.ARCHIVED {
+		events = append(events, application.ProductRestored{ProductEvent: event})
+		status = application.DISABLED
+	}
+	if status != product.GetStatus() {
+		switch product.GetStatus() {
+		case application.ENABLED:
+			events = append(events, application.ProductEnabled{ProductEvent: event})
+		case application.DISABLED:
+			events = append(events, application.ProductDisabled{ProductEvent: event})
+		case application.ARCHIVED:
+			archived := event
+			if deletedAt := product.GetDeletedAt(); deletedAt != nil {
+				archived.OccurredAt = deletedAt.UTC()
+			}
+			events = append(events, application.ProductArchived{ProductEvent: archived})
+		}
+	}
+	return events
+}
+
+const (
+	selectStream   = `select version from product_streams where product_id = ?`
+	selectSnapshot = `select event_id, payload from product_snapshots where product_id = ?`
+	selectEvents   = `select event_name, payload from product_events where product_id = ? and id > ? order by id`
+	insertEvent    = `insert into product_events(product_id, version, event_name, payload, actor, occurred_at) values(?, ?, ?, ?, ?, ?)`
+	saveSnapshot   = `insert into product_snapshots(product_id, event_id, payload) values(?, ?, ?)
+		on conflict(product_id) do update set event_id = excluded.event_id, payload = excluded.payload`
+)
+
+// Streams start at version 1, so a product that claims a later version but
+// comes back as a fresh stream was purged by another operation.
+const saveStream = `insert into product_streams(product_id, version) values(?, 1)
+	on conflict(product_id) do update set version = product_streams.version + 1
+	where product_streams.version = ?
+	returning version`
+
+func (s *ProductEventStore) Save(ctx context.Context, product application.ProductInterface) (application.ProductInterface, error) {
+	if s.store.tx == nil {
+		for _, query := range []string{selectStream, selectSnapshot, selectEvents, saveStream, insertEvent, saveSnapshot, insertOutbox} {
+			if _, err := s.store.prepare(ctx, query); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var version int
+	err := s.transaction(ctx, func(tx *ProductEventStore) error {
+		previous, replayed, err := tx.load(ctx, product.GetId())
+		if err != nil {
+			return err
+		}
+		stmt, err := tx.store.prepare(ctx, saveStream)
+		if err != nil {
+			return err
+		}
+
+		err = stmt.QueryRowContext(ctx, product.GetId(), product.GetVersion()).Scan(&version)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && version != product.GetVersion()+1) {
+			return fmt.Errorf("%w: %s", application.ErrConcurrentModification, product.GetId())
+		}
+		if err != nil {
+			return err
+		}
+
+		events := streamEvents(previous, product)
+		lastId, err := tx.appendEvents(ctx, version, events)
+		if err != nil {
+			return err
+		}
+		if len(events) > 0 && tx.SnapshotInterval > 0 && replayed+len(events) >= tx.SnapshotInterval {
+			if err := tx.writeSnapshot(ctx, product, lastId); err != nil {
+				return err
+			}
+		}
+		if tx.store.outbox {
+			if err := tx.store.writeOutbox(ctx, product.Events()); err != nil {
+				return err
+			}
+		}
+		tx.store.setVersion(product, version)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func (s *ProductEventStore) appendEvents(ctx context.Context, version int, events []application.DomainEvent) (int64, error) {
+	stmt, err := s.store.prepare(ctx, insertEvent)
+	if err != nil {
+		return 0, err
+	}
+
+	var lastId int64
+	actor := application.ActorFromContext(ctx)
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return 0, err
+		}
+		result, err := stmt.ExecContext(ctx, event.GetProductId(), version, event.EventName(), string(payload), actor, event.GetOccurredAt())
+		if err != nil {
+			return 0, err
+		}
+		if lastId, err = result.LastInsertId(); err != nil {
+			return 0, err
+		}
+	}
+	return lastId, nil
+}
+
+func (s *ProductEventStore) writeSnapshot(ctx context.Context, product application.ProductInterface, eventId int64) error {
+	payload, err := json.Marshal(application.Product{
+		Id:        product.GetId(),
+		Name:      product.GetName(),
+		Price:     product.GetPrice(),
+		Status:    product.GetStatus(),
+		DeletedAt: product.GetDeletedAt(),
+	})
+	if err != nil {
+		return err
+	}
+
+	stmt, err := s.store.prepare(ctx, saveSnapshot)
+	if err != nil {
+		return err
+	}
+	_, err = stmt.ExecContext(ctx, product.GetId(), eventId, string(payload))
+	return err
+}
+
+// History is derived from the stream: the events appended by one save become
+// one version, listing every field they changed.
+func (s *ProductEventStore) History(ctx context.Context, id string) ([]application.ProductChange, error) {
+	rows, err := s.store.conn().QueryContext(ctx, "select version, event_name, payload, actor, occurred_at from product_events where product_id = ? order by id", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []application.ProductChange
+	var product *application.Product
+	var previous application.ProductInterface
+	var version int
+	var actor string
+	var changedAt time.Time
+	flush := func() {
+		changes = append(changes, application.DiffProduct(previous, product, version, actor, changedAt)...)
+		saved := *product
+		previous = &saved
+	}
+
+	for rows.Next() {
+		var eventVersion int
+		var name, payload string
+		var eventActor string
+		var occurredAt time.Time
+		if err := rows.Scan(&eventVersion, &name, &payload, &eventActor, &occurredAt); err != nil {
+			return nil, err
+		}
+		if product != nil && eventVersion != version {
+			flush()
+		}
+		event, err := application.UnmarshalEvent(name, []byte(payload))
+		if err != nil {
+			return nil, err
+		}
+		if product, err = apply(product, event); err != nil {
+			return nil, err
+		}
+		version, actor, changedAt = eventVersion, eventActor, occurredAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, fmt.Errorf("%w: %s", application.ErrProductNotFound, id)
+	}
+	flush()
+	return changes, nil
+}
+
+// Delete purges the whole stream, as purging a ProductDb row purges its
+// history, and queues a product.purged event behind the undelivered ones.
+func (s *ProductEventStore) Delete(ctx context.Context, id string) error {
+	if s.store.tx == nil && s.store.outbox {
+		if _, err := s.store.prepare(ctx, insertOutbox); err != nil {
+			return err
+		}
+	}
+	return s.transaction(ctx, func(tx *ProductEventStore) error {
+		result, err := tx.store.conn().ExecContext(ctx, "delete from product_streams where product_id = ?", id)
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return fmt.Errorf("%w: %s", application.ErrProductNotFound, id)
+		}
+
+		for _, query := range []string{"delete from product_events where product_id = ?", "delete from product_snapshots where product_id = ?"} {
+			if _, err := tx.store.conn().ExecContext(ctx, query, id); err != nil {
+				return err
+			}
+		}
+		return tx.store.writePurged(ctx, id)
+	})
+}
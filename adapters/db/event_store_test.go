@@ -0,0 +1,160 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/db"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/stretchr/testify/assert"
+)
+
+func countRows(t *testing.T, table, productId string) int {
+	var count int
+	assert.Nil(t, Db.QueryRow("select count(*) from "+table+" where product_id = ?", productId).Scan(&count))
+	return count
+}
+
+func TestProductEventStoreSave(t *testing.T) {
+	setUp()
+	defer Db.Close()
+
+	store := db.NewProductEventStore(Db)
+	defer store.Close()
+
+	product := application.NewProduct("Product 2", application.NewMoney(2000, application.DEFAULT_CURRENCY))
+	assert.Nil(t, product.Enable())
+
+	t.Run("Success - Append the changes of every save", func(t *testing.T) {
+		_, err := store.Save(context.Background(), product)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, countRows(t, "product_events", product.GetId()))
+
+		assert.Nil(t, product.ChangeName("Product 2b"))
+		assert.Nil(t, product.ChangePrice(application.NewMoney(2500, application.DEFAULT_CURRENCY)))
+		_, err = store.Save(context.Background(), product)
+		assert.Nil(t, err)
+		assert.Equal(t, 4, countRows(t, "product_events", product.GetId()))
+	})
+
+	t.Run("Success - Saving an unchanged product only bumps the version", func(t *testing.T) {
+		stored, err := store.Get(context.Background(), product.GetId())
+		assert.Nil(t, err)
+		result, err := store.Save(context.Background(), stored)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, result.GetVersion())
+		assert.Equal(t, 4, countRows(t, "product_events", product.GetId()))
+	})
+
+	t.Run("Error - A stale save appends nothing", func(t *testing.T) {
+		stale := &application.Product{Id: product.GetId(), Name: "Stale", Price: application.NewMoney(2500, application.DEFAULT_CURRENCY), Status: "enabled", Version: 1}
+		_, err := store.Save(context.Background(), stale)
+		assert.ErrorIs(t, err, application.ErrConcurrentModification)
+		assert.Equal(t, 4, countRows(t, "product_events", product.GetId()))
+	})
+}
+
+func TestProductEventStoreSnapshot(t *testing.T) {
+	setUp()
+	defer Db.Close()
+
+	store := db.NewProductEventStore(Db)
+	store.SnapshotInterval = 3
+	defer store.Close()
+
+	product := application.NewProduct("Product 2", application.NewMoney(2000, application.DEFAULT_CURRENCY))
+	_, err := store.Save(context.Background(), product)
+	assert.Nil(t, err)
+	for _, amount := range []int64{2100, 2200, 2300, 2400} {
+		assert.Nil(t, product.ChangePrice(application.NewMoney(amount, application.DEFAULT_CURRENCY)))
+		_, err := store.Save(context.Background(), product)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 1, countRows(t, "product_snapshots", product.GetId()))
+
+	t.Run("Success - Replay the events after the snapshot", func(t *testing.T) {
+		stored, err := store.Get(context.Background(), product.GetId())
+		assert.Nil(t, err)
+		assert.Equal(t, application.NewMoney(2400, application.DEFAULT_CURRENCY), stored.GetPrice())
+		assert.Equal(t, 5, stored.GetVersion())
+	})
+
+	t.Run("Success - The snapshot does not shorten the history", func(t *testing.T) {
+		changes, err := store.History(context.Background(), product.GetId())
+		assert.Nil(t, err)
+		assert.Len(t, changes, 7)
+		assert.Equal(t, "23.00 BRL", *changes[6].OldValue)
+		assert.Equal(t, "24.00 BRL", changes[6].NewValue)
+	})
+
+	t.Run("Error - Events can not be rewritten", func(t *testing.T) {
+		_, err := Db.Exec("update product_events set payload = '{}'")
+		assert.ErrorContains(t, err, "product_events is append-only")
+	})
+}
+
+func TestProductEventStoreHistory(t *testing.T) {
+	setUp()
+	defer Db.Close()
+
+	store := db.NewProductEventStore(Db)
+	defer store.Close()
+
+	product := application.NewProduct("Product 2", application.NewMoney(2000, application.DEFAULT_CURRENCY))
+	assert.Nil(t, product.Enable())
+	_, err := store.Save(application.WithActor(context.Background(), "alice"), product)
+	assert.Nil(t, err)
+	assert.Nil(t, product.ChangeName("Product 2b"))
+	_, err = store.Save(application.WithActor(context.Background(), "bob"), product)
+	assert.Nil(t, err)
+
+	changes, err := store.History(context.Background(), product.GetId())
+	assert.Nil(t, err)
+	assert.Len(t, changes, 4)
+	assert.Equal(t, "status", changes[2].Field)
+	assert.Nil(t, changes[2].OldValue)
+	assert.Equal(t, application.ENABLED, changes[2].NewValue)
+	assert.Equal(t, "alice", changes[2].Actor)
+	assert.Equal(t, 2, changes[3].Version)
+	assert.Equal(t, "Product 2", *changes[3].OldValue)
+	assert.Equal(t, "bob", changes[3].Actor)
+
+	_, err = store.History(context.Background(), "missing")
+	assert.ErrorIs(t, err, application.ErrProductNotFound)
+}
+
+func TestProductEventStoreWithinTransaction(t *testing.T) {
+	setUp()
+	defer Db.Close()
+
+	store := db.NewProductEventStore(Db).WithOutbox()
+	defer store.Close()
+
+	product := application.NewProduct("Product 2", application.NewMoney(2000, application.DEFAULT_CURRENCY))
+	err := store.WithinTransaction(context.Background(), func(ctx context.Context, persistence application.ProductPersistenceInterface) error {
+		if _, err := persistence.Save(ctx, product); err != nil {
+			return err
+		}
+		return errors.New("abort")
+	})
+	assert.EqualError(t, err, "abort")
+	assert.Equal(t, 0, countRows(t, "product_events", product.GetId()))
+	assert.Equal(t, 0, countRows(t, "outbox", product.GetId()))
+
+	saved := application.NewProduct("Product 3", application.NewMoney(3000, application.DEFAULT_CURRENCY))
+	_, err = store.Save(context.Background(), saved)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, countRows(t, "outbox", saved.GetId()))
+
+	assert.Nil(t, store.Delete(context.Background(), saved.GetId()))
+	assert.Equal(t, 0, countRows(t, "product_events", saved.GetId()))
+	assert.Equal(t, 2, countRows(t, "outbox", saved.GetId()))
+}
+
+func TestProductEventStoreConcurrentWriters(t *testing.T) {
+	saveConcurrently(t, func(conn *sql.DB) closablePersistence {
+		return db.NewProductEventStore(conn).WithOutbox()
+	})
+}
@@ -0,0 +1,4 @@
+package db
+
+// SaveProduct is the upsert ProductDb.Save runs, for the benchmark.
+const SaveProduct = saveProduct
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+)
+
+const insertHistory = "insert into product_history(product_id, version, field, old_value, new_value, actor, changed_at) values(?, ?, ?, ?, ?, ?, ?)"
+
+// previousState decodes the fields an update replaced, which saveProduct
+// returns so the history needs no read before it, or nil for an insert.
+func previousState(id string, state sql.NullString) (application.ProductInterface, error) {
+	if !state.Valid {
+		return nil, nil
+	}
+	var fields struct {
+		Name     string `json:"name"`
+		Amount   int64  `json:"amount"`
+		Currency string `json:"currency"`
+		Status   string `json:"status"`
+	}
+	if err := json.Unmarshal([]byte(state.String), &fields); err != nil {
+		return nil, err
+	}
+	return &application.Product{Id: id, Name: fields.Name, Price: application.NewMoney(fields.Amount, fields.Currency), Status: fields.Status}, nil
+}
+
+func (p *ProductDb) writeHistory(ctx context.Context, previous, product application.ProductInterface, version int) error {
+	stmt, err := p.prepare(ctx, insertHistory)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range application.DiffProduct(previous, product, version, application.ActorFromContext(ctx), time.Now().UTC()) {
+		if _, err := stmt.ExecContext(ctx, change.ProductId, change.Version, change.Field, change.OldValue, change.NewValue, change.Actor, change.ChangedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *ProductDb) History(ctx context.Context, id string) ([]application.ProductChange, error) {
+	rows, err := p.conn().QueryContext(ctx, "select version, field, old_value, new_value, actor, changed_at from product_history where product_id = ? order by id", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []application.ProductChange
+	for rows.Next() {
+		change := application.ProductChange{ProductId: id}
+		var old sql.NullString
+		if err := rows.Scan(&change.Version, &change.Field, &old, &change.NewValue, &change.Actor, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		if old.Valid {
+			change.OldValue = &old.String
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(changes) > 0 {
+		return changes, nil
+	}
+
+	var exists int
+	if err := p.conn().QueryRowContext(ctx, "select count(*) from products where id = ?", id).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		return nil, fmt.Errorf("%w: %s", application.ErrProductNotFound, id)
+	}
+	return changes, nil
+}
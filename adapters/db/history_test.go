@@ -0,0 +1,106 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/db"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductDbHistory(t *testing.T) {
+	setUp()
+	defer Db.Close()
+
+	productDb := db.NewProductDb(Db)
+	defer productDb.Close()
+
+	product := application.NewProduct("Product 2", application.NewMoney(2000, application.DEFAULT_CURRENCY))
+	ctx := application.WithActor(context.Background(), "alice")
+
+	t.Run("Success - Record every field of a new product", func(t *testing.T) {
+		_, err := productDb.Save(ctx, product)
+		assert.Nil(t, err)
+
+		changes, err := productDb.History(context.Background(), product.GetId())
+		assert.Nil(t, err)
+		assert.Len(t, changes, 3)
+		for _, change := range changes {
+			assert.Equal(t, 1, change.Version)
+			assert.Nil(t, change.OldValue)
+			assert.Equal(t, "alice", change.Actor)
+		}
+		assert.Equal(t, "price", changes[1].Field)
+		assert.Equal(t, "20.00 BRL", changes[1].NewValue)
+	})
+
+	t.Run("Success - Record only the changed fields", func(t *testing.T) {
+		assert.Nil(t, product.ChangePrice(application.NewMoney(2500, application.DEFAULT_CURRENCY)))
+		assert.Nil(t, product.Enable())
+		_, err := productDb.Save(application.WithActor(context.Background(), "bob"), product)
+		assert.Nil(t, err)
+
+		changes, err := productDb.History(context.Background(), product.GetId())
+		assert.Nil(t, err)
+		assert.Len(t, changes, 5)
+		assert.Equal(t, 2, changes[3].Version)
+		assert.Equal(t, "bob", changes[3].Actor)
+		assert.Equal(t, "price", changes[3].Field)
+		assert.Equal(t, "20.00 BRL", *changes[3].OldValue)
+		assert.Equal(t, "25.00 BRL", changes[3].NewValue)
+		assert.Equal(t, "status", changes[4].Field)
+		assert.Equal(t, "disabled", *changes[4].OldValue)
+		assert.Equal(t, "enabled", changes[4].NewValue)
+	})
+
+	t.Run("Error - A stale save records nothing", func(t *testing.T) {
+		stale := &application.Product{Id: product.GetId(), Name: "Stale", Price: application.NewMoney(2500, application.DEFAULT_CURRENCY), Status: "enabled", Version: 1}
+		_, err := productDb.Save(ctx, stale)
+		assert.ErrorIs(t, err, application.ErrConcurrentModification)
+
+		changes, err := productDb.History(context.Background(), product.GetId())
+		assert.Nil(t, err)
+		assert.Len(t, changes, 5)
+	})
+
+	t.Run("Error - The history is append-only", func(t *testing.T) {
+		_, err := Db.Exec("update product_history set actor = 'mallory'")
+		assert.ErrorContains(t, err, "product_history is append-only")
+		_, err = Db.Exec("delete from product_history")
+		assert.ErrorContains(t, err, "product_history is append-only")
+	})
+
+	t.Run("Success - A product without recorded changes has an empty history", func(t *testing.T) {
+		changes, err := productDb.History(context.Background(), "1")
+		assert.Nil(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("Error - History of a product that does not exist", func(t *testing.T) {
+		_, err := productDb.History(context.Background(), "missing")
+		assert.ErrorIs(t, err, application.ErrProductNotFound)
+	})
+
+	t.Run("Success - Purging a product erases its history but not its pending events", func(t *testing.T) {
+		outboxDb := db.NewProductDb(Db).WithOutbox()
+		defer outboxDb.Close()
+
+		secret := application.NewProduct("Alice Secret", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+		_, err := outboxDb.Save(ctx, secret)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, countRows(t, "product_history", secret.GetId()))
+		assert.Equal(t, 1, countRows(t, "outbox", secret.GetId()))
+
+		assert.Nil(t, outboxDb.Delete(context.Background(), secret.GetId()))
+		_, err = outboxDb.History(context.Background(), secret.GetId())
+		assert.ErrorIs(t, err, application.ErrProductNotFound)
+		assert.Equal(t, 0, countRows(t, "product_history", secret.GetId()))
+		assert.Equal(t, 2, countRows(t, "outbox", secret.GetId()))
+
+		var payload string
+		err = Db.QueryRow("select payload from outbox where product_id = ? and event_name = ?", secret.GetId(), application.PRODUCT_PURGED).Scan(&payload)
+		assert.Nil(t, err)
+		assert.NotContains(t, payload, "Alice Secret")
+	})
+}
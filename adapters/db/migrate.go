@@ -0,0 +1,239 @@
+package db
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func Migrate(db *sql.DB) error {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	_, err = migrator.Up()
+	return err
+}
+
+func (m *Migrator) Up() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := m.run(migration.Up, "insert into schema_migrations(version, name, applied_at) values(?, ?, ?)", migration.Version, migration.Name, time.Now().UTC())
+		if err != nil {
+			return result, fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		result = append(result, migration)
+	}
+	return result, nil
+}
+
+func (m *Migrator) Down() (*Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		err := m.run(migration.Down, "delete from schema_migrations where version = ?", migration.Version)
+		if err != nil {
+			return nil, fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		return &migration, nil
+	}
+	return nil, nil
+}
+
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+func (m *Migrator) applied() (map[int]time.Time, error) {
+	var tracked int
+	err := m.db.QueryRow("select count(*) from sqlite_master where type = 'table' and name = 'schema_migrations'").Scan(&tracked)
+	if err != nil {
+		return nil, err
+	}
+	if tracked == 0 {
+		if err := m.baseline(); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := m.db.Query("select version, applied_at from schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		result[version] = appliedAt
+	}
+	return result, rows.Err()
+}
+
+// Databases created before migrations were tracked already have the products
+// table with money columns, so the migrations that built it are recorded as applied.
+func (m *Migrator) baseline() error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`create table schema_migrations (
+		version integer primary key,
+		name string not null,
+		applied_at datetime not null
+	)`)
+	if err != nil {
+		return err
+	}
+
+	var existing int
+	err = tx.QueryRow("select count(*) from pragma_table_info('products') where name = 'price_amount'").Scan(&existing)
+	if err != nil {
+		return err
+	}
+	if existing > 0 {
+		for _, migration := range m.migrations {
+			if migration.Version > 2 {
+				break
+			}
+			_, err := tx.Exec("insert into schema_migrations(version, name, applied_at) values(?, ?, ?)", migration.Version, migration.Name, time.Now().UTC())
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (m *Migrator) run(script, record string, args ...any) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(script); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func loadMigrations(files fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(files, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := map[int]*Migration{}
+	for _, name := range names {
+		base := path.Base(name)
+		rest, direction, ok := strings.Cut(strings.TrimSuffix(base, ".sql"), ".")
+		versionText, migrationName, found := strings.Cut(rest, "_")
+		version, err := strconv.Atoi(versionText)
+		if !ok || !found || err != nil || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("invalid migration file name %q", base)
+		}
+
+		content, err := fs.ReadFile(files, name)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, exists := migrations[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: migrationName}
+			migrations[version] = migration
+		}
+		if migration.Name != migrationName {
+			return nil, fmt.Errorf("migration %04d has conflicting names %q and %q", version, migration.Name, migrationName)
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	result := make([]Migration, 0, len(migrations))
+	for _, migration := range migrations {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d must have both up and down scripts", migration.Version)
+		}
+		result = append(result, *migration)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
@@ -0,0 +1,208 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/db"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrator(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	conn.SetMaxOpenConns(1)
+	defer conn.Close()
+
+	migrator, err := db.NewMigrator(conn)
+	assert.Nil(t, err)
+
+	t.Run("Success - Status before migrating", func(t *testing.T) {
+		result, err := migrator.Status()
+		assert.Nil(t, err)
+		assert.NotEmpty(t, result)
+		for _, status := range result {
+			assert.Nil(t, status.AppliedAt)
+		}
+	})
+
+	t.Run("Success - Up applies every pending migration", func(t *testing.T) {
+		applied, err := migrator.Up()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, applied[0].Version)
+		assert.Equal(t, "create_products", applied[0].Name)
+
+		status, err := migrator.Status()
+		assert.Nil(t, err)
+		assert.Len(t, applied, len(status))
+		for _, migration := range status {
+			assert.NotNil(t, migration.AppliedAt)
+		}
+
+		applied, err = migrator.Up()
+		assert.Nil(t, err)
+		assert.Empty(t, applied)
+	})
+
+	t.Run("Success - Down rolls back one migration at a time", func(t *testing.T) {
+		status, err := migrator.Status()
+		assert.Nil(t, err)
+
+		for i := len(status) - 1; i >= 0; i-- {
+			migration, err := migrator.Down()
+			assert.Nil(t, err)
+			assert.Equal(t, status[i].Version, migration.Version)
+		}
+
+		migration, err := migrator.Down()
+		assert.Nil(t, err)
+		assert.Nil(t, migration)
+
+		var tables int
+		err = conn.QueryRow("select count(*) from sqlite_master where type = 'table' and name = 'products'").Scan(&tables)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, tables)
+	})
+
+	t.Run("Success - Up after rolling everything back", func(t *testing.T) {
+		assert.Nil(t, db.Migrate(conn))
+
+		productDb := db.NewProductDb(conn)
+		product := application.NewProduct("Product 1", application.NewMoney(1999, application.DEFAULT_CURRENCY))
+		_, err := productDb.Save(context.Background(), product)
+		assert.Nil(t, err)
+	})
+}
+
+func TestMigrateLegacyFloatPrices(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	conn.SetMaxOpenConns(1)
+	defer conn.Close()
+
+	_, err := conn.Exec(`create table products (
+		id string primary key,
+		name string not null,
+		price float,
+		status string not null
+	)`)
+	assert.Nil(t, err)
+	_, err = conn.Exec(`insert into products(id, name, price, status) values
+		('1', 'Product 1', 19.99, 'enabled'),
+		('2', 'Product 2', 0.1, 'enabled'),
+		('3', 'Product 3', null, 'disabled')`)
+	assert.Nil(t, err)
+
+	assert.Nil(t, db.Migrate(conn))
+
+	productDb := db.NewProductDb(conn)
+	expected := map[string]application.Money{
+		"1": application.NewMoney(1999, application.DEFAULT_CURRENCY),
+		"2": application.NewMoney(10, application.DEFAULT_CURRENCY),
+		"3": application.NewMoney(0, application.DEFAULT_CURRENCY),
+	}
+	for id, price := range expected {
+		result, err := productDb.Get(context.Background(), id)
+		assert.Nil(t, err)
+		assert.Equal(t, price, result.GetPrice())
+	}
+}
+
+func TestMigrateUntrackedMoneySchema(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	conn.SetMaxOpenConns(1)
+	defer conn.Close()
+
+	_, err := conn.Exec(`create table products (
+		id string primary key,
+		name string not null,
+		price_amount integer not null default 0,
+		price_currency string not null default 'BRL',
+		status string not null
+	)`)
+	assert.Nil(t, err)
+
+	migrator, err := db.NewMigrator(conn)
+	assert.Nil(t, err)
+
+	applied, err := migrator.Up()
+	assert.Nil(t, err)
+	for _, migration := range applied {
+		assert.Greater(t, migration.Version, 2)
+	}
+
+	status, err := migrator.Status()
+	assert.Nil(t, err)
+	for _, migration := range status {
+		assert.NotNil(t, migration.AppliedAt)
+	}
+}
+
+func TestMigrateDownKeepsArchivedProducts(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	conn.SetMaxOpenConns(1)
+	defer conn.Close()
+
+	assert.Nil(t, db.Migrate(conn))
+	productDb := db.NewProductDb(conn)
+	product := application.NewProduct("Product 1", application.NewMoney(1999, application.DEFAULT_CURRENCY))
+	assert.Nil(t, product.Archive())
+	_, err := productDb.Save(context.Background(), product)
+	assert.Nil(t, err)
+
+	migrator, err := db.NewMigrator(conn)
+	assert.Nil(t, err)
+	for {
+		migration, err := migrator.Down()
+		if !assert.Nil(t, err) || !assert.NotNil(t, migration) {
+			t.FailNow()
+		}
+		if migration.Version == 3 {
+			break
+		}
+	}
+
+	var status string
+	err = conn.QueryRow("select status from products where id = ?", product.GetId()).Scan(&status)
+	assert.Nil(t, err)
+	assert.Equal(t, application.DISABLED, status)
+}
+
+func TestMigrateDownMoneyPrices(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	conn.SetMaxOpenConns(1)
+	defer conn.Close()
+
+	assert.Nil(t, db.Migrate(conn))
+	productDb := db.NewProductDb(conn)
+	expected := map[string]float64{}
+	for price, value := range map[application.Money]float64{
+		application.NewMoney(1999, "BRL"): 19.99,
+		application.NewMoney(1500, "JPY"): 1500,
+		application.NewMoney(1250, "KWD"): 1.25,
+	} {
+		product := application.NewProduct("Product "+price.Currency, price)
+		_, err := productDb.Save(context.Background(), product)
+		assert.Nil(t, err)
+		expected[product.GetId()] = value
+	}
+
+	migrator, err := db.NewMigrator(conn)
+	assert.Nil(t, err)
+	for {
+		migration, err := migrator.Down()
+		if !assert.Nil(t, err) || !assert.NotNil(t, migration) {
+			t.FailNow()
+		}
+		if migration.Version == 2 {
+			break
+		}
+	}
+
+	for id, value := range expected {
+		var price float64
+		err := conn.QueryRow("select price from products where id = ?", id).Scan(&price)
+		assert.Nil(t, err)
+		assert.Equal(t, value, price)
+	}
+}
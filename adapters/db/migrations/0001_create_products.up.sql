@@ -0,0 +1,6 @@
+create table if not exists products (
+	id string primary key,
+	name string not null,
+	price float,
+	status string not null
+);
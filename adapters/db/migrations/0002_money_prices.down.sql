@@ -0,0 +1,9 @@
+alter table products add column price float;
+-- The exponents follow application.CurrencyExponent.
+update products set price = price_amount / case
+	when price_currency in ('BHD', 'IQD', 'JOD', 'KWD', 'LYD', 'OMR', 'TND') then 1000.0
+	when price_currency in ('BIF', 'CLP', 'DJF', 'GNF', 'ISK', 'JPY', 'KMF', 'KRW', 'PYG', 'RWF', 'UGX', 'UYI', 'VND', 'VUV', 'XAF', 'XOF', 'XPF') then 1.0
+	else 100.0
+end;
+alter table products drop column price_currency;
+alter table products drop column price_amount;
@@ -0,0 +1,4 @@
+alter table products add column price_amount integer not null default 0;
+alter table products add column price_currency string not null default 'BRL';
+update products set price_amount = cast(round(coalesce(price, 0) * 100) as integer);
+alter table products drop column price;
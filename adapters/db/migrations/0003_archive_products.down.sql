@@ -0,0 +1,2 @@
+update products set status = 'disabled' where deleted_at is not null;
+alter table products drop column deleted_at;
@@ -0,0 +1 @@
+alter table products add column deleted_at datetime;
@@ -0,0 +1 @@
+alter table products drop column version;
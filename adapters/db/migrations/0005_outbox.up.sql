@@ -0,0 +1,12 @@
+create table outbox (
+	id integer primary key autoincrement,
+	event_name string not null,
+	product_id string not null,
+	payload text not null,
+	occurred_at datetime not null,
+	attempts integer not null default 0,
+	next_attempt_at datetime not null,
+	last_error string,
+	delivered_at datetime
+);
+create index outbox_pending on outbox(delivered_at, next_attempt_at);
@@ -0,0 +1 @@
+drop table product_history;
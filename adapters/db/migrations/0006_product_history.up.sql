@@ -0,0 +1,19 @@
+create table product_history (
+	id integer primary key autoincrement,
+	product_id string not null,
+	version integer not null,
+	field string not null,
+	old_value string,
+	new_value string not null,
+	actor string not null,
+	changed_at datetime not null
+);
+create index product_history_product on product_history(product_id, id);
+create trigger product_history_no_update before update on product_history
+begin
+	select raise(abort, 'product_history is append-only');
+end;
+create trigger product_history_no_delete before delete on product_history
+begin
+	select raise(abort, 'product_history is append-only');
+end;
@@ -0,0 +1,3 @@
+drop table product_snapshots;
+drop table product_events;
+drop table product_streams;
@@ -0,0 +1,23 @@
+create table product_streams (
+	product_id string primary key,
+	version integer not null
+);
+create table product_events (
+	id integer primary key autoincrement,
+	product_id string not null,
+	version integer not null,
+	event_name string not null,
+	payload text not null,
+	actor string not null,
+	occurred_at datetime not null
+);
+create index product_events_stream on product_events(product_id, id);
+create trigger product_events_no_update before update on product_events
+begin
+	select raise(abort, 'product_events is append-only');
+end;
+create table product_snapshots (
+	product_id string primary key,
+	event_id integer not null,
+	payload text not null
+);
@@ -0,0 +1,5 @@
+drop trigger product_history_no_delete;
+create trigger product_history_no_delete before delete on product_history
+begin
+	select raise(abort, 'product_history is append-only');
+end;
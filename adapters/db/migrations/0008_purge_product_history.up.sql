@@ -0,0 +1,6 @@
+drop trigger product_history_no_delete;
+create trigger product_history_no_delete before delete on product_history
+when exists (select 1 from products where id = old.product_id)
+begin
+	select raise(abort, 'product_history is append-only');
+end;
@@ -0,0 +1 @@
+alter table products add column previous_state string;
@@ -0,0 +1,22 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// Open opens a SQLite database for the adapters in this package. Saves read
+// the stored product before writing it, and SQLite fails a deferred
+// transaction that does so with "database is locked" when another one is
+// writing, without waiting for the busy timeout. Transactions are therefore
+// started with BEGIN IMMEDIATE, so they queue for the write lock instead.
+func Open(dsn string) (*sql.DB, error) {
+	if !strings.Contains(dsn, "_txlock=") {
+		separator := "?"
+		if strings.Contains(dsn, "?") {
+			separator = "&"
+		}
+		dsn += separator + "_txlock=immediate"
+	}
+	return sql.Open("sqlite3", dsn)
+}
@@ -0,0 +1,174 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+)
+
+const insertOutbox = "insert into outbox(event_name, product_id, payload, occurred_at, next_attempt_at) values(?, ?, ?, ?, ?)"
+
+func (p *ProductDb) writeOutbox(ctx context.Context, events []application.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	stmt, err := p.prepare(ctx, insertOutbox)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		_, err = stmt.ExecContext(ctx, event.EventName(), event.GetProductId(), string(payload), event.GetOccurredAt().UTC(), event.GetOccurredAt().UTC())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OutboxRelay publishes the events written by ProductDb.WithOutbox. Events of
+// a product are delivered in the order they were saved: while one of them is
+// waiting for a retry, the later ones wait too. After MaxAttempts failures an
+// event is left in the table undelivered for an operator to inspect.
+type OutboxRelay struct {
+	db           *sql.DB
+	publisher    application.EventPublisherInterface
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	Retention    time.Duration
+}
+
+func NewOutboxRelay(db *sql.DB, publisher application.EventPublisherInterface) *OutboxRelay {
+	return &OutboxRelay{
+		db:           db,
+		publisher:    publisher,
+		PollInterval: time.Second,
+		BatchSize:    100,
+		MaxAttempts:  10,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   5 * time.Minute,
+		Retention:    24 * time.Hour,
+	}
+}
+
+// Run relays until ctx is done. Storage errors are logged and the relay keeps
+// polling, backing off while they repeat.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	failures := 0
+	for {
+		wait := r.PollInterval
+		if err := r.poll(ctx); err != nil && ctx.Err() == nil {
+			failures++
+			wait = max(r.backoff(failures), r.PollInterval)
+			log.Printf("outbox relay: %v", err)
+		} else {
+			failures = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (r *OutboxRelay) poll(ctx context.Context) error {
+	if _, err := r.Relay(ctx); err != nil {
+		return err
+	}
+	_, err := r.Cleanup(ctx)
+	return err
+}
+
+func (r *OutboxRelay) Relay(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	rows, err := r.db.QueryContext(ctx, `select id, event_name, product_id, payload, attempts from outbox
+		where delivered_at is null and attempts < ? and next_attempt_at <= ?
+		and not exists (
+			select 1 from outbox earlier
+			where earlier.product_id = outbox.product_id and earlier.id < outbox.id
+			and earlier.delivered_at is null and earlier.attempts < ?
+		)
+		order by id limit ?`, r.MaxAttempts, now, r.MaxAttempts, r.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	type pending struct {
+		id        int64
+		eventName string
+		productId string
+		payload   string
+		attempts  int
+	}
+	var entries []pending
+	for rows.Next() {
+		var entry pending
+		if err := rows.Scan(&entry.id, &entry.eventName, &entry.productId, &entry.payload, &entry.attempts); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		entries = append(entries, entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	failed := map[string]bool{}
+	for _, entry := range entries {
+		if failed[entry.productId] {
+			continue
+		}
+		event, err := application.UnmarshalEvent(entry.eventName, []byte(entry.payload))
+		if err == nil {
+			err = r.publisher.Publish(ctx, event)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return delivered, ctx.Err()
+			}
+			_, updateErr := r.db.ExecContext(ctx, "update outbox set attempts = attempts + 1, next_attempt_at = ?, last_error = ? where id = ?", now.Add(r.backoff(entry.attempts+1)), err.Error(), entry.id)
+			if updateErr != nil {
+				return delivered, updateErr
+			}
+			failed[entry.productId] = true
+			continue
+		}
+
+		if _, err := r.db.ExecContext(ctx, "update outbox set delivered_at = ?, last_error = null where id = ?", time.Now().UTC(), entry.id); err != nil {
+			return delivered, err
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	backoff := r.BaseBackoff
+	for i := 1; i < attempts && backoff < r.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, r.MaxBackoff)
+}
+
+func (r *OutboxRelay) Cleanup(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, "delete from outbox where delivered_at is not null and delivered_at < ?", time.Now().UTC().Add(-r.Retention))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
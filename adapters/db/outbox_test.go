@@ -0,0 +1,211 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/db"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/stretchr/testify/assert"
+)
+
+type publisherFunc func(ctx context.Context, events ...application.DomainEvent) error
+
+func (f publisherFunc) Publish(ctx context.Context, events ...application.DomainEvent) error {
+	return f(ctx, events...)
+}
+
+func countOutbox(t *testing.T, condition string) int {
+	var count int
+	assert.Nil(t, Db.QueryRow("select count(*) from outbox where "+condition).Scan(&count))
+	return count
+}
+
+func TestProductDbOutbox(t *testing.T) {
+	setUp()
+	defer Db.Close()
+
+	productDb := db.NewProductDb(Db).WithOutbox()
+	defer productDb.Close()
+
+	product := application.NewProduct("Product 2", application.NewMoney(2000, application.DEFAULT_CURRENCY))
+	assert.Nil(t, product.Enable())
+
+	t.Run("Success - Save writes the events with the product", func(t *testing.T) {
+		_, err := productDb.Save(context.Background(), product)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, countOutbox(t, "product_id = '"+product.GetId()+"' and delivered_at is null"))
+	})
+
+	t.Run("Error - A failed save writes no events", func(t *testing.T) {
+		stale := &application.Product{Id: product.GetId(), Name: "Product 2", Price: application.NewMoney(2000, application.DEFAULT_CURRENCY), Status: "enabled", Version: 9}
+		assert.Nil(t, stale.ChangePrice(application.NewMoney(2500, application.DEFAULT_CURRENCY)))
+
+		_, err := productDb.Save(context.Background(), stale)
+		assert.ErrorIs(t, err, application.ErrConcurrentModification)
+		assert.Equal(t, 2, countOutbox(t, "1 = 1"))
+	})
+
+	t.Run("Error - A rolled back transaction writes no events", func(t *testing.T) {
+		err := productDb.WithinTransaction(context.Background(), func(ctx context.Context, persistence application.ProductPersistenceInterface) error {
+			if _, err := persistence.Save(ctx, application.NewProduct("Product 3", application.NewMoney(3000, application.DEFAULT_CURRENCY))); err != nil {
+				return err
+			}
+			return errors.New("abort")
+		})
+		assert.EqualError(t, err, "abort")
+		assert.Equal(t, 2, countOutbox(t, "1 = 1"))
+	})
+}
+
+func TestOutboxRelay(t *testing.T) {
+	setUp()
+	defer Db.Close()
+
+	productDb := db.NewProductDb(Db).WithOutbox()
+	defer productDb.Close()
+
+	first := application.NewProduct("Product 2", application.NewMoney(2000, application.DEFAULT_CURRENCY))
+	assert.Nil(t, first.Enable())
+	second := application.NewProduct("Product 3", application.NewMoney(3000, application.DEFAULT_CURRENCY))
+	for _, product := range []*application.Product{first, second} {
+		_, err := productDb.Save(context.Background(), product)
+		assert.Nil(t, err)
+		product.ClearEvents()
+	}
+
+	var published []application.DomainEvent
+	failing := map[string]bool{first.GetId(): true}
+	relay := db.NewOutboxRelay(Db, publisherFunc(func(ctx context.Context, events ...application.DomainEvent) error {
+		if failing[events[0].GetProductId()] {
+			return errors.New("broker unavailable")
+		}
+		published = append(published, events...)
+		return nil
+	}))
+	relay.BaseBackoff = time.Hour
+	relay.MaxAttempts = 2
+
+	t.Run("Error - A failing event holds back the later events of its product", func(t *testing.T) {
+		delivered, err := relay.Relay(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, delivered)
+		assert.Len(t, published, 1)
+		created := published[0].(application.ProductCreated)
+		assert.Equal(t, second.GetId(), created.GetProductId())
+		assert.Equal(t, application.NewMoney(3000, application.DEFAULT_CURRENCY), created.Price)
+
+		assert.Equal(t, 1, countOutbox(t, "attempts = 1 and last_error = 'broker unavailable' and next_attempt_at > datetime('now')"))
+		assert.Equal(t, 1, countOutbox(t, "attempts = 0 and delivered_at is null"))
+	})
+
+	t.Run("Success - Wait for the backoff before retrying", func(t *testing.T) {
+		delete(failing, first.GetId())
+
+		delivered, err := relay.Relay(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 0, delivered)
+	})
+
+	t.Run("Success - Deliver the events in order once the backoff elapsed", func(t *testing.T) {
+		relay.BaseBackoff = 0
+		_, err := Db.Exec("update outbox set next_attempt_at = ? where attempts > 0", time.Now().UTC().Add(-time.Second))
+		assert.Nil(t, err)
+
+		delivered, err := relay.Relay(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, delivered)
+		delivered, err = relay.Relay(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, delivered)
+
+		assert.Equal(t, application.PRODUCT_CREATED, published[1].EventName())
+		assert.Equal(t, application.PRODUCT_ENABLED, published[2].EventName())
+		assert.Equal(t, 0, countOutbox(t, "delivered_at is null"))
+	})
+
+	t.Run("Error - Give up after the maximum attempts", func(t *testing.T) {
+		assert.Nil(t, first.ChangePrice(application.NewMoney(2500, application.DEFAULT_CURRENCY)))
+		_, err := productDb.Save(context.Background(), first)
+		assert.Nil(t, err)
+		failing[first.GetId()] = true
+
+		for range 3 {
+			_, err := relay.Relay(context.Background())
+			assert.Nil(t, err)
+		}
+		assert.Equal(t, 1, countOutbox(t, "attempts = 2 and delivered_at is null"))
+	})
+
+	t.Run("Success - Clean up the delivered events", func(t *testing.T) {
+		relay.Retention = 0
+		deleted, err := relay.Cleanup(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), deleted)
+		assert.Equal(t, 1, countOutbox(t, "1 = 1"))
+	})
+}
+
+func TestOutboxRelayRun(t *testing.T) {
+	conn, err := db.Open(filepath.Join(t.TempDir(), "sqlite.db"))
+	assert.Nil(t, err)
+	defer conn.Close()
+	assert.Nil(t, db.Migrate(conn))
+
+	productDb := db.NewProductDb(conn).WithOutbox()
+	defer productDb.Close()
+	product := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+	_, err = productDb.Save(context.Background(), product)
+	assert.Nil(t, err)
+
+	published := make(chan application.DomainEvent, 1)
+	relay := db.NewOutboxRelay(conn, publisherFunc(func(ctx context.Context, events ...application.DomainEvent) error {
+		for _, event := range events {
+			published <- event
+		}
+		return nil
+	}))
+	relay.PollInterval = 5 * time.Millisecond
+	relay.BaseBackoff = 5 * time.Millisecond
+	relay.MaxBackoff = 20 * time.Millisecond
+
+	// Every pass fails while the outbox table is missing.
+	_, err = conn.Exec("alter table outbox rename to outbox_moved")
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(stopped)
+	}()
+
+	t.Run("Error - A failed pass does not stop the relay", func(t *testing.T) {
+		time.Sleep(50 * time.Millisecond)
+		select {
+		case <-stopped:
+			t.Fatal("the relay stopped")
+		default:
+		}
+		assert.Empty(t, published)
+	})
+
+	t.Run("Success - A later pass delivers the events", func(t *testing.T) {
+		_, err := conn.Exec("alter table outbox_moved rename to outbox")
+		assert.Nil(t, err)
+
+		select {
+		case event := <-published:
+			assert.Equal(t, application.PRODUCT_CREATED, event.EventName())
+			assert.Equal(t, product.GetId(), event.GetProductId())
+		case <-time.After(time.Second):
+			t.Fatal("the event was not delivered")
+		}
+	})
+
+	cancel()
+	<-stopped
+}
@@ -1,49 +1,158 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
 )
 
 type ProductDb struct {
-	db *sql.DB
+	db         *sql.DB
+	tx         *sql.Tx
+	statements *statements
+	outbox     bool
+	// rollback undoes the changes saves made to products when tx does not commit.
+	rollback *[]func()
+}
+
+type statements struct {
+	mu    sync.Mutex
+	stmts map[string]*sql.Stmt
+}
+
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
 
 func NewProductDb(db *sql.DB) *ProductDb {
-	return &ProductDb{db: db}
+	return &ProductDb{db: db, statements: &statements{stmts: map[string]*sql.Stmt{}}}
+}
+
+// Saves made through the returned adapter also write the product's pending
+// events to the outbox table in the same transaction, for an OutboxRelay to publish.
+func (p *ProductDb) WithOutbox() *ProductDb {
+	p.outbox = true
+	return p
 }
 
-func (p *ProductDb) Get(id string) (application.ProductInterface, error) {
-	stmt, err := p.db.Prepare("select id, name, price, status from products where id = ?")
+func (p *ProductDb) conn() querier {
+	if p.tx != nil {
+		return p.tx
+	}
+	return p.db
+}
+
+func (p *ProductDb) prepare(ctx context.Context, query string) (*sql.Stmt, error) {
+	p.statements.mu.Lock()
+	defer p.statements.mu.Unlock()
+
+	stmt, ok := p.statements.stmts[query]
+	if p.tx != nil {
+		// The transaction may hold the only pooled connection, so statements
+		// that are not cached yet are prepared on it and dropped with it.
+		if !ok {
+			return p.tx.PrepareContext(ctx, query)
+		}
+		return p.tx.StmtContext(ctx, stmt), nil
+	}
+	if ok {
+		return stmt, nil
+	}
+
+	stmt, err := p.db.PrepareContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
-	defer stmt.Close()
+	p.statements.stmts[query] = stmt
+	return stmt, nil
+}
 
-	var product application.Product
-	err = stmt.QueryRow(id).Scan(&product.Id, &product.Name, &product.Price, &product.Status)
+func (p *ProductDb) Close() error {
+	p.statements.mu.Lock()
+	defer p.statements.mu.Unlock()
+
+	var errs []error
+	for query, stmt := range p.statements.stmts {
+		errs = append(errs, stmt.Close())
+		delete(p.statements.stmts, query)
+	}
+	return errors.Join(errs...)
+}
+
+// Calls made while a transaction is already open join it instead of starting a new one.
+func (p *ProductDb) WithinTransaction(ctx context.Context, fn func(ctx context.Context, persistence application.ProductPersistenceInterface) error) error {
+	return p.transaction(ctx, func(tx *ProductDb) error {
+		return fn(ctx, tx)
+	})
+}
+
+func (p *ProductDb) transaction(ctx context.Context, fn func(tx *ProductDb) error) error {
+	if p.tx != nil {
+		return fn(p)
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	defer tx.Rollback()
 
-	return &product, nil
+	var rollback []func()
+	err = fn(&ProductDb{db: p.db, tx: tx, statements: p.statements, outbox: p.outbox, rollback: &rollback})
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		for i := len(rollback) - 1; i >= 0; i-- {
+			rollback[i]()
+		}
+		return err
+	}
+	return nil
+}
+
+// setVersion moves the product to its saved version, and back if the
+// transaction does not commit, so it can be saved again.
+func (p *ProductDb) setVersion(product application.ProductInterface, version int) {
+	previous := product.GetVersion()
+	product.SetVersion(version)
+	*p.rollback = append(*p.rollback, func() {
+		product.SetVersion(previous)
+	})
 }
 
-func (p *ProductDb) Save(product application.ProductInterface) (application.ProductInterface, error) {
-	var rows int
-	err := p.db.QueryRow("select count(id) from products where id = ?", product.GetId()).Scan(&rows)
+const productColumns = "id, name, price_amount, price_currency, status, deleted_at, version"
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func (p *ProductDb) Get(ctx context.Context, id string) (application.ProductInterface, error) {
+	return p.get(ctx, "select "+productColumns+" from products where id = ? and deleted_at is null", id)
+}
+
+func (p *ProductDb) GetArchived(ctx context.Context, id string) (application.ProductInterface, error) {
+	return p.get(ctx, "select "+productColumns+" from products where id = ? and deleted_at is not null", id)
+}
+
+func (p *ProductDb) get(ctx context.Context, query, id string) (application.ProductInterface, error) {
+	stmt, err := p.prepare(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	if rows == 0 {
-		err = p.create(product)
-	} else {
-		err = p.update(product)
+	product, err := scanProduct(stmt.QueryRowContext(ctx, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", application.ErrProductNotFound, id)
 	}
-
 	if err != nil {
 		return nil, err
 	}
@@ -51,32 +160,199 @@ func (p *ProductDb) Save(product application.ProductInterface) (application.Prod
 	return product, nil
 }
 
-func (p *ProductDb) create(product application.ProductInterface) error {
-	stmt, err := p.db.Prepare("insert into products(id, name, price, status) values(?, ?, ?, ?)")
+func scanProduct(row scanner) (*application.Product, error) {
+	var product application.Product
+	var deletedAt sql.NullTime
+	err := row.Scan(&product.Id, &product.Name, &product.Price.Amount, &product.Price.Currency, &product.Status, &deletedAt, &product.Version)
+	if err != nil {
+		return nil, err
+	}
+	if deletedAt.Valid {
+		product.DeletedAt = &deletedAt.Time
+	}
+	return &product, nil
+}
+
+func (p *ProductDb) List(ctx context.Context, query application.ProductQuery) (application.ProductPage, error) {
+	var page application.ProductPage
+	query, err := query.Normalize()
 	if err != nil {
-		return err
+		return page, err
+	}
+
+	column := "name"
+	if query.SortBy == application.SORT_BY_PRICE {
+		column = "price_amount"
+	}
+	direction, comparison := "asc", ">"
+	if query.Desc {
+		direction, comparison = "desc", "<"
 	}
-	defer stmt.Close()
 
-	_, err = stmt.Exec(product.GetId(), product.GetName(), product.GetPrice(), product.GetStatus())
+	var conditions []string
+	var args []any
+	if query.Status == "" {
+		conditions = append(conditions, "deleted_at is null")
+	} else {
+		conditions = append(conditions, "status = ?")
+		args = append(args, query.Status)
+	}
+	if query.Name != "" {
+		conditions = append(conditions, `name like ? escape '\'`)
+		args = append(args, "%"+escapeLike(query.Name)+"%")
+	}
+	if query.MinPrice != nil {
+		conditions = append(conditions, "price_currency = ? and price_amount >= ?")
+		args = append(args, query.MinPrice.Currency, query.MinPrice.Amount)
+	}
+	if query.MaxPrice != nil {
+		conditions = append(conditions, "price_currency = ? and price_amount <= ?")
+		args = append(args, query.MaxPrice.Currency, query.MaxPrice.Amount)
+	}
+	if query.Cursor != "" {
+		cursor, err := application.DecodeProductCursor(query.Cursor)
+		if err != nil {
+			return page, err
+		}
+		var value any = cursor.Name
+		if query.SortBy == application.SORT_BY_PRICE {
+			value = cursor.Price
+		}
+		conditions = append(conditions, "("+column+" "+comparison+" ? or ("+column+" = ? and id "+comparison+" ?))")
+		args = append(args, value, value, cursor.Id)
+	}
+
+	sqlQuery := "select " + productColumns + " from products where " + strings.Join(conditions, " and ")
+	sqlQuery += " order by " + column + " " + direction + ", id " + direction + " limit ?"
+	args = append(args, query.Limit+1)
+
+	rows, err := p.conn().QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
-		return err
+		return page, err
 	}
+	defer rows.Close()
 
-	return nil
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return page, err
+		}
+		page.Products = append(page.Products, product)
+	}
+	if err := rows.Err(); err != nil {
+		return page, err
+	}
+
+	if len(page.Products) > query.Limit {
+		page.Products = page.Products[:query.Limit]
+		page.NextCursor = application.NewProductCursor(page.Products[query.Limit-1], query.SortBy).Encode()
+	}
+
+	return page, nil
 }
 
-func (p *ProductDb) update(product application.ProductInterface) error {
-	stmt, err := p.db.Prepare("update products set name = ?, price = ?, status = ? where id = ?")
-	if err != nil {
-		return err
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// Rows are inserted with version 1, so a product that claims a later version
+// but comes back as a fresh insert was purged by another operation. Updates
+// keep the fields they replace in previous_state and return them for the
+// history.
+const saveProduct = `insert into products(id, name, price_amount, price_currency, status, deleted_at, version)
+	values(?, ?, ?, ?, ?, ?, 1)
+	on conflict(id) do update set
+		name = excluded.name,
+		price_amount = excluded.price_amount,
+		price_currency = excluded.price_currency,
+		status = excluded.status,
+		deleted_at = excluded.deleted_at,
+		version = products.version + 1,
+		previous_state = json_object('name', products.name, 'amount', products.price_amount, 'currency', products.price_currency, 'status', products.status)
+	where products.version = ?
+	returning version, previous_state`
+
+func (p *ProductDb) Save(ctx context.Context, product application.ProductInterface) (application.ProductInterface, error) {
+	// Statements are cached only when prepared outside a transaction, so
+	// every one the save uses is prepared before it starts.
+	if p.tx == nil {
+		for _, query := range []string{saveProduct, insertHistory, insertOutbox} {
+			if _, err := p.prepare(ctx, query); err != nil {
+				return nil, err
+			}
+		}
 	}
-	defer stmt.Close()
 
-	_, err = stmt.Exec(product.GetName(), product.GetPrice(), product.GetStatus(), product.GetId())
+	var version int
+	err := p.transaction(ctx, func(tx *ProductDb) error {
+		stmt, err := tx.prepare(ctx, saveProduct)
+		if err != nil {
+			return err
+		}
+
+		var state sql.NullString
+		err = stmt.QueryRowContext(ctx, product.GetId(), product.GetName(), product.GetPrice().Amount, product.GetPrice().Currency, product.GetStatus(), product.GetDeletedAt(), product.GetVersion()).Scan(&version, &state)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && version != product.GetVersion()+1) {
+			return fmt.Errorf("%w: %s", application.ErrConcurrentModification, product.GetId())
+		}
+		if err != nil {
+			return err
+		}
+		previous, err := previousState(product.GetId(), state)
+		if err != nil {
+			return err
+		}
+		if err := tx.writeHistory(ctx, previous, product, version); err != nil {
+			return err
+		}
+		if tx.outbox {
+			if err := tx.writeOutbox(ctx, product.Events()); err != nil {
+				return err
+			}
+		}
+		tx.setVersion(product, version)
+		return nil
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return product, nil
+}
 
-	return nil
+// Delete purges the product with its history, so nothing it was called or
+// priced at is left behind. The history stays append-only while the product
+// exists. Undelivered outbox rows are kept and followed by a product.purged
+// event, so subscribers still learn everything that happened to it.
+func (p *ProductDb) Delete(ctx context.Context, id string) error {
+	if p.tx == nil && p.outbox {
+		if _, err := p.prepare(ctx, insertOutbox); err != nil {
+			return err
+		}
+	}
+	return p.transaction(ctx, func(tx *ProductDb) error {
+		result, err := tx.conn().ExecContext(ctx, "delete from products where id = ?", id)
+		if err != nil {
+			return err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return fmt.Errorf("%w: %s", application.ErrProductNotFound, id)
+		}
+
+		if _, err := tx.conn().ExecContext(ctx, "delete from product_history where product_id = ?", id); err != nil {
+			return err
+		}
+		return tx.writePurged(ctx, id)
+	})
+}
+
+func (p *ProductDb) writePurged(ctx context.Context, id string) error {
+	if !p.outbox {
+		return nil
+	}
+	return p.writeOutbox(ctx, []application.DomainEvent{application.NewProductPurged(id)})
 }
@@ -1,9 +1,17 @@
 package db_test
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/db"
 	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
@@ -14,36 +22,20 @@ var Db *sql.DB
 
 func setUp() {
 	Db, _ = sql.Open("sqlite3", ":memory:")
-	createTable(Db)
-	createProduct(Db)
-}
-
-func createTable(db *sql.DB) {
-	createTable := `create table products (
-		id string primary key,
-		name string not null,
-		price float,
-		status string not null
-	)`
-	stmt, err := db.Prepare(createTable)
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer stmt.Close()
-	_, err = stmt.Exec()
-	if err != nil {
+	if err := db.Migrate(Db); err != nil {
 		log.Fatal(err)
 	}
+	createProduct(Db)
 }
 
 func createProduct(db *sql.DB) {
-	insertProduct := `insert into products(id, name, price, status) values(?, ?, ?, ?)`
+	insertProduct := `insert into products(id, name, price_amount, price_currency, status) values(?, ?, ?, ?, ?)`
 	stmt, err := db.Prepare(insertProduct)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer stmt.Close()
-	_, err = stmt.Exec("1", "Product 1", 10.0, "enabled")
+	_, err = stmt.Exec("1", "Product 1", 1000, "BRL", "enabled")
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -56,16 +48,25 @@ func TestProductDbGet(t *testing.T) {
 	productDb := db.NewProductDb(Db)
 
 	t.Run("Success - Get a product", func(t *testing.T) {
-		result, err := productDb.Get("1")
+		result, err := productDb.Get(context.Background(), "1")
 		assert.Nil(t, err)
 		assert.Equal(t, "Product 1", result.GetName())
-		assert.Equal(t, 10.0, result.GetPrice())
+		assert.Equal(t, application.NewMoney(1000, application.DEFAULT_CURRENCY), result.GetPrice())
 		assert.Equal(t, "enabled", result.GetStatus())
 	})
 
 	t.Run("Error - Get a product that does not exist", func(t *testing.T) {
-		result, err := productDb.Get("2")
-		assert.NotNil(t, err)
+		result, err := productDb.Get(context.Background(), "2")
+		assert.ErrorIs(t, err, application.ErrProductNotFound)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Error - Get with a cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		result, err := productDb.Get(ctx, "1")
+		assert.ErrorIs(t, err, context.Canceled)
 		assert.Nil(t, result)
 	})
 }
@@ -80,27 +81,560 @@ func TestProductDbSave(t *testing.T) {
 		product := &application.Product{
 			Id:     "2",
 			Name:   "Product 2",
-			Price:  20.0,
+			Price:  application.NewMoney(2000, application.DEFAULT_CURRENCY),
 			Status: "enabled",
 		}
-		result, err := productDb.Save(product)
+		result, err := productDb.Save(context.Background(), product)
 		assert.Nil(t, err)
 		assert.Equal(t, "Product 2", result.GetName())
-		assert.Equal(t, 20.0, result.GetPrice())
+		assert.Equal(t, application.NewMoney(2000, application.DEFAULT_CURRENCY), result.GetPrice())
 		assert.Equal(t, "enabled", result.GetStatus())
 	})
 
+	t.Run("Error - Save with an expired deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+		defer cancel()
+
+		product := application.NewProduct("Product 3", application.NewMoney(3000, application.DEFAULT_CURRENCY))
+		result, err := productDb.Save(ctx, product)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, result)
+	})
+
 	t.Run("Success - Update a product", func(t *testing.T) {
+		product := &application.Product{
+			Id:      "1",
+			Name:    "Product 1",
+			Price:   application.NewMoney(1000, application.DEFAULT_CURRENCY),
+			Status:  "disabled",
+			Version: 1,
+		}
+		result, err := productDb.Save(context.Background(), product)
+		assert.Nil(t, err)
+		assert.Equal(t, "Product 1", result.GetName())
+		assert.Equal(t, application.NewMoney(1000, application.DEFAULT_CURRENCY), result.GetPrice())
+		assert.Equal(t, "disabled", result.GetStatus())
+		assert.Equal(t, 2, result.GetVersion())
+	})
+
+	t.Run("Error - Update a product with a stale version", func(t *testing.T) {
+		product := &application.Product{
+			Id:      "1",
+			Name:    "Product 1",
+			Price:   application.NewMoney(2000, application.DEFAULT_CURRENCY),
+			Status:  "disabled",
+			Version: 1,
+		}
+		result, err := productDb.Save(context.Background(), product)
+		assert.ErrorIs(t, err, application.ErrConcurrentModification)
+		assert.Nil(t, result)
+
+		stored, err := productDb.Get(context.Background(), "1")
+		assert.Nil(t, err)
+		assert.Equal(t, application.NewMoney(1000, application.DEFAULT_CURRENCY), stored.GetPrice())
+	})
+
+	t.Run("Error - Create a product with an existing id", func(t *testing.T) {
 		product := &application.Product{
 			Id:     "1",
 			Name:   "Product 1",
-			Price:  10.0,
+			Price:  application.NewMoney(3000, application.DEFAULT_CURRENCY),
 			Status: "disabled",
 		}
-		result, err := productDb.Save(product)
+		result, err := productDb.Save(context.Background(), product)
+		assert.ErrorIs(t, err, application.ErrConcurrentModification)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Error - Save a purged product", func(t *testing.T) {
+		product := &application.Product{
+			Id:      "9",
+			Name:    "Product 9",
+			Price:   application.NewMoney(3000, application.DEFAULT_CURRENCY),
+			Status:  "disabled",
+			Version: 3,
+		}
+		result, err := productDb.Save(context.Background(), product)
+		assert.ErrorIs(t, err, application.ErrConcurrentModification)
+		assert.Nil(t, result)
+	})
+}
+
+func TestProductDbConcurrentSave(t *testing.T) {
+	setUp()
+	defer Db.Close()
+
+	productDb := db.NewProductDb(Db)
+	first, err := productDb.Get(context.Background(), "1")
+	assert.Nil(t, err)
+	second, err := productDb.Get(context.Background(), "1")
+	assert.Nil(t, err)
+
+	assert.Nil(t, first.ChangePrice(application.NewMoney(1500, application.DEFAULT_CURRENCY)))
+	_, err = productDb.Save(context.Background(), first)
+	assert.Nil(t, err)
+
+	assert.Nil(t, second.ChangePrice(application.NewMoney(2500, application.DEFAULT_CURRENCY)))
+	_, err = productDb.Save(context.Background(), second)
+	assert.ErrorIs(t, err, application.ErrConcurrentModification)
+
+	result, err := productDb.Get(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.Equal(t, application.NewMoney(1500, application.DEFAULT_CURRENCY), result.GetPrice())
+	assert.Equal(t, 2, result.GetVersion())
+}
+
+func TestProductDbList(t *testing.T) {
+	setUp()
+	defer Db.Close()
+
+	productDb := db.NewProductDb(Db)
+	products := []*application.Product{
+		{Id: "2", Name: "Product 2", Price: application.NewMoney(3000, application.DEFAULT_CURRENCY), Status: "disabled"},
+		{Id: "3", Name: "Special_Product 3", Price: application.NewMoney(500, application.DEFAULT_CURRENCY), Status: "enabled"},
+		{Id: "4", Name: "Product 4", Price: application.NewMoney(2000, application.DEFAULT_CURRENCY), Status: "enabled"},
+	}
+	for _, product := range products {
+		_, err := productDb.Save(context.Background(), product)
 		assert.Nil(t, err)
-		assert.Equal(t, "Product 1", result.GetName())
-		assert.Equal(t, 10.0, result.GetPrice())
-		assert.Equal(t, "disabled", result.GetStatus())
+	}
+
+	ids := func(page application.ProductPage) []string {
+		result := []string{}
+		for _, product := range page.Products {
+			result = append(result, product.GetId())
+		}
+		return result
+	}
+
+	t.Run("Success - List sorted by name", func(t *testing.T) {
+		result, err := productDb.List(context.Background(), application.ProductQuery{})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"1", "2", "4", "3"}, ids(result))
+		assert.Empty(t, result.NextCursor)
+	})
+
+	t.Run("Success - List sorted by price descending", func(t *testing.T) {
+		result, err := productDb.List(context.Background(), application.ProductQuery{SortBy: application.SORT_BY_PRICE, Desc: true})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"2", "4", "1", "3"}, ids(result))
+	})
+
+	t.Run("Success - List following the cursor", func(t *testing.T) {
+		query := application.ProductQuery{SortBy: application.SORT_BY_PRICE, Limit: 3}
+		first, err := productDb.List(context.Background(), query)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"3", "1", "4"}, ids(first))
+		assert.NotEmpty(t, first.NextCursor)
+
+		query.Cursor = first.NextCursor
+		second, err := productDb.List(context.Background(), query)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"2"}, ids(second))
+		assert.Empty(t, second.NextCursor)
+	})
+
+	t.Run("Success - List filtered by status and price range", func(t *testing.T) {
+		minPrice := application.NewMoney(600, application.DEFAULT_CURRENCY)
+		maxPrice := application.NewMoney(2000, application.DEFAULT_CURRENCY)
+		result, err := productDb.List(context.Background(), application.ProductQuery{Status: application.ENABLED, MinPrice: &minPrice, MaxPrice: &maxPrice})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"1", "4"}, ids(result))
+	})
+
+	t.Run("Success - List filtered by name", func(t *testing.T) {
+		result, err := productDb.List(context.Background(), application.ProductQuery{Name: "l_p"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"3"}, ids(result))
+
+		result, err = productDb.List(context.Background(), application.ProductQuery{Name: "product"})
+		assert.Nil(t, err)
+		assert.Len(t, result.Products, 4)
+	})
+
+	t.Run("Error - List with an invalid query", func(t *testing.T) {
+		_, err := productDb.List(context.Background(), application.ProductQuery{Status: "invalid"})
+		assert.NotNil(t, err)
+	})
+}
+
+func TestProductDbArchive(t *testing.T) {
+	setUp()
+	defer Db.Close()
+
+	productDb := db.NewProductDb(Db)
+	product, err := productDb.Get(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.Nil(t, product.Archive())
+	_, err = productDb.Save(context.Background(), product)
+	assert.Nil(t, err)
+
+	t.Run("Success - Archived products are hidden from reads", func(t *testing.T) {
+		result, err := productDb.Get(context.Background(), "1")
+		assert.ErrorIs(t, err, application.ErrProductNotFound)
+		assert.Nil(t, result)
+
+		page, err := productDb.List(context.Background(), application.ProductQuery{})
+		assert.Nil(t, err)
+		assert.Empty(t, page.Products)
+	})
+
+	t.Run("Success - Archived products can be read explicitly", func(t *testing.T) {
+		result, err := productDb.GetArchived(context.Background(), "1")
+		assert.Nil(t, err)
+		assert.Equal(t, application.ARCHIVED, result.GetStatus())
+		assert.NotNil(t, result.GetDeletedAt())
+
+		page, err := productDb.List(context.Background(), application.ProductQuery{Status: application.ARCHIVED})
+		assert.Nil(t, err)
+		assert.Len(t, page.Products, 1)
+	})
+
+	t.Run("Success - Restored products are visible again", func(t *testing.T) {
+		result, err := productDb.GetArchived(context.Background(), "1")
+		assert.Nil(t, err)
+		assert.Nil(t, result.Restore())
+		_, err = productDb.Save(context.Background(), result)
+		assert.Nil(t, err)
+
+		result, err = productDb.Get(context.Background(), "1")
+		assert.Nil(t, err)
+		assert.Equal(t, application.DISABLED, result.GetStatus())
+		assert.Nil(t, result.GetDeletedAt())
+
+		_, err = productDb.GetArchived(context.Background(), "1")
+		assert.ErrorIs(t, err, application.ErrProductNotFound)
+	})
+}
+
+func TestProductDbDelete(t *testing.T) {
+	setUp()
+	defer Db.Close()
+
+	productDb := db.NewProductDb(Db)
+
+	t.Run("Success - Delete a product", func(t *testing.T) {
+		assert.Nil(t, productDb.Delete(context.Background(), "1"))
+
+		_, err := productDb.Get(context.Background(), "1")
+		assert.ErrorIs(t, err, application.ErrProductNotFound)
+	})
+
+	t.Run("Error - Delete a product that does not exist", func(t *testing.T) {
+		err := productDb.Delete(context.Background(), "1")
+		assert.ErrorIs(t, err, application.ErrProductNotFound)
+	})
+}
+
+func TestProductDbClose(t *testing.T) {
+	setUp()
+	defer Db.Close()
+
+	productDb := db.NewProductDb(Db)
+	_, err := productDb.Get(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.Nil(t, productDb.Close())
+
+	result, err := productDb.Get(context.Background(), "1")
+	assert.Nil(t, err)
+	assert.Equal(t, "Product 1", result.GetName())
+}
+
+func TestProductDbWithinTransaction(t *testing.T) {
+	setUp()
+	defer Db.Close()
+	Db.SetMaxOpenConns(1)
+
+	productDb := db.NewProductDb(Db)
+	defer productDb.Close()
+
+	t.Run("Success - Commit every save", func(t *testing.T) {
+		first := application.NewProduct("Product 2", application.NewMoney(2000, application.DEFAULT_CURRENCY))
+		second := application.NewProduct("Product 3", application.NewMoney(3000, application.DEFAULT_CURRENCY))
+
+		err := productDb.WithinTransaction(context.Background(), func(ctx context.Context, persistence application.ProductPersistenceInterface) error {
+			if _, err := persistence.Save(ctx, first); err != nil {
+				return err
+			}
+			_, err := persistence.Save(ctx, second)
+			return err
+		})
+		assert.Nil(t, err)
+
+		for _, product := range []*application.Product{first, second} {
+			result, err := productDb.Get(context.Background(), product.GetId())
+			assert.Nil(t, err)
+			assert.Equal(t, product.GetName(), result.GetName())
+		}
+	})
+
+	t.Run("Error - Roll back every save", func(t *testing.T) {
+		product := application.NewProduct("Product 4", application.NewMoney(4000, application.DEFAULT_CURRENCY))
+		stale := &application.Product{Id: "1", Name: "Product 1", Price: application.NewMoney(1000, application.DEFAULT_CURRENCY), Status: "disabled", Version: 7}
+
+		err := productDb.WithinTransaction(context.Background(), func(ctx context.Context, persistence application.ProductPersistenceInterface) error {
+			if _, err := persistence.Save(ctx, product); err != nil {
+				return err
+			}
+			_, err := persistence.Save(ctx, stale)
+			return err
+		})
+		assert.ErrorIs(t, err, application.ErrConcurrentModification)
+
+		_, err = productDb.Get(context.Background(), product.GetId())
+		assert.ErrorIs(t, err, application.ErrProductNotFound)
+	})
+
+	t.Run("Success - Nested transactions join the outer one", func(t *testing.T) {
+		product := application.NewProduct("Product 5", application.NewMoney(5000, application.DEFAULT_CURRENCY))
+
+		err := productDb.WithinTransaction(context.Background(), func(ctx context.Context, persistence application.ProductPersistenceInterface) error {
+			inner := persistence.(application.ProductTransactionInterface)
+			err := inner.WithinTransaction(ctx, func(ctx context.Context, persistence application.ProductPersistenceInterface) error {
+				_, err := persistence.Save(ctx, product)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if err := persistence.Delete(ctx, "1"); err != nil {
+				return err
+			}
+			return errors.New("abort")
+		})
+		assert.EqualError(t, err, "abort")
+
+		_, err = productDb.Get(context.Background(), product.GetId())
+		assert.ErrorIs(t, err, application.ErrProductNotFound)
+		_, err = productDb.Get(context.Background(), "1")
+		assert.Nil(t, err)
+	})
+}
+
+func TestProductDbBatch(t *testing.T) {
+	setUp()
+	defer Db.Close()
+
+	productDb := db.NewProductDb(Db)
+	defer productDb.Close()
+	service := application.NewProductService(productDb)
+
+	inputs := []application.ProductInput{
+		{Name: "Product 2", Price: application.NewMoney(2000, application.DEFAULT_CURRENCY)},
+		{Name: "Product 3", Price: application.NewMoney(3000, application.DEFAULT_CURRENCY)},
+	}
+
+	t.Run("Success - Create every product", func(t *testing.T) {
+		report, err := service.CreateMany(context.Background(), inputs, application.BATCH_ALL_OR_NOTHING)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, report.Succeeded())
+
+		for _, result := range report.Results {
+			product, err := productDb.Get(context.Background(), result.Id)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, product.GetVersion())
+		}
+	})
+
+	t.Run("Error - Leave every product untouched when one item fails", func(t *testing.T) {
+		page, err := productDb.List(context.Background(), application.ProductQuery{})
+		assert.Nil(t, err)
+		ids := []string{}
+		for _, product := range page.Products {
+			ids = append(ids, product.GetId())
+		}
+
+		report, err := service.DisableMany(context.Background(), ids, application.BATCH_ALL_OR_NOTHING)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, report.Failed())
+
+		product, err := productDb.Get(context.Background(), "1")
+		assert.Nil(t, err)
+		assert.Equal(t, application.ENABLED, product.GetStatus())
+	})
+}
+
+func TestProductDbCsv(t *testing.T) {
+	setUp()
+	defer Db.Close()
+
+	productDb := db.NewProductDb(Db)
+	defer productDb.Close()
+	service := application.NewProductService(productDb)
+
+	var csv strings.Builder
+	csv.WriteString("id,name,price,status\n")
+	for i := range application.MAX_PAGE_SIZE + 20 {
+		fmt.Fprintf(&csv, ",Imported %03d,%d.50,enabled\n", i, i+1)
+	}
+
+	t.Run("Success - Dry run leaves the catalog untouched", func(t *testing.T) {
+		report, err := service.Import(context.Background(), strings.NewReader(csv.String()), true)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, report.Failed())
+
+		page, err := productDb.List(context.Background(), application.ProductQuery{Limit: application.MAX_PAGE_SIZE})
+		assert.Nil(t, err)
+		assert.Len(t, page.Products, 1)
+	})
+
+	t.Run("Success - Import then export every page", func(t *testing.T) {
+		report, err := service.Import(context.Background(), strings.NewReader(csv.String()), false)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, report.Failed())
+
+		var exported strings.Builder
+		assert.Nil(t, service.Export(context.Background(), &exported, application.ProductQuery{Status: application.ENABLED}))
+		lines := strings.Split(strings.TrimSpace(exported.String()), "\n")
+		assert.Len(t, lines, application.MAX_PAGE_SIZE+22)
+		assert.Equal(t, "id,name,price,status,currency", lines[0])
+		assert.True(t, strings.HasSuffix(lines[1], ",Imported 000,1.50,enabled,BRL"))
+	})
+}
+
+// saveBeforeUpsert is ProductDb.Save as it was before the upsert: a count
+// round trip, then an insert or a versioned update prepared on every call.
+func saveBeforeUpsert(ctx context.Context, conn *sql.DB, product application.ProductInterface) error {
+	var rows int
+	err := conn.QueryRowContext(ctx, "select count(id) from products where id = ?", product.GetId()).Scan(&rows)
+	if err != nil {
+		return err
+	}
+
+	query := "update products set name = ?, price_amount = ?, price_currency = ?, status = ?, deleted_at = ?, version = version + 1 where id = ? and version = ?"
+	if rows == 0 && product.GetVersion() == 0 {
+		query = "insert into products(name, price_amount, price_currency, status, deleted_at, id, version) values(?, ?, ?, ?, ?, ?, ? + 1)"
+	}
+	stmt, err := conn.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, product.GetName(), product.GetPrice().Amount, product.GetPrice().Currency, product.GetStatus(), product.GetDeletedAt(), product.GetId(), product.GetVersion())
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+		return fmt.Errorf("%w: %s", application.ErrConcurrentModification, product.GetId())
+	}
+	product.SetVersion(product.GetVersion() + 1)
+	return nil
+}
+
+// upsert runs the statement of ProductDb.Save on its own, without the
+// transaction, history and outbox around it.
+func upsert(ctx context.Context, stmt *sql.Stmt, product application.ProductInterface) error {
+	var version int
+	var state sql.NullString
+	err := stmt.QueryRowContext(ctx, product.GetId(), product.GetName(), product.GetPrice().Amount, product.GetPrice().Currency, product.GetStatus(), product.GetDeletedAt(), product.GetVersion()).Scan(&version, &state)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && version != product.GetVersion()+1) {
+		return fmt.Errorf("%w: %s", application.ErrConcurrentModification, product.GetId())
+	}
+	if err != nil {
+		return err
+	}
+	product.SetVersion(version)
+	return nil
+}
+
+// BenchmarkProductDbSave compares the upsert with the count-then-write save
+// it replaced, with concurrent writers. Save adds the transaction, history
+// and outbox to the upsert.
+func BenchmarkProductDbSave(b *testing.B) {
+	strategies := map[string]func(*sql.DB) func(context.Context, application.ProductInterface) error{
+		"Upsert": func(conn *sql.DB) func(context.Context, application.ProductInterface) error {
+			stmt, err := conn.Prepare(db.SaveProduct)
+			if err != nil {
+				b.Fatal(err)
+			}
+			return func(ctx context.Context, product application.ProductInterface) error {
+				return upsert(ctx, stmt, product)
+			}
+		},
+		"CountThenWrite": func(conn *sql.DB) func(context.Context, application.ProductInterface) error {
+			return func(ctx context.Context, product application.ProductInterface) error {
+				return saveBeforeUpsert(ctx, conn, product)
+			}
+		},
+		"Save": func(conn *sql.DB) func(context.Context, application.ProductInterface) error {
+			productDb := db.NewProductDb(conn).WithOutbox()
+			return func(ctx context.Context, product application.ProductInterface) error {
+				_, err := productDb.Save(ctx, product)
+				return err
+			}
+		},
+	}
+
+	for name, strategy := range strategies {
+		b.Run(name, func(b *testing.B) {
+			dsn := filepath.Join(b.TempDir(), "bench.db") + "?_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate"
+			conn, err := sql.Open("sqlite3", dsn)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer conn.Close()
+			if err := db.Migrate(conn); err != nil {
+				b.Fatal(err)
+			}
+
+			save := strategy(conn)
+			var writers atomic.Int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				product := application.NewProduct(fmt.Sprintf("Product %d", writers.Add(1)), application.NewMoney(1000, application.DEFAULT_CURRENCY))
+				for pb.Next() {
+					if err := save(context.Background(), product); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
+
+type closablePersistence interface {
+	application.ProductPersistenceInterface
+	Close() error
+}
+
+// saveConcurrently creates products from several goroutines on a file
+// database, where SQLite locking applies, and fails on any error.
+func saveConcurrently(t *testing.T, open func(*sql.DB) closablePersistence) {
+	conn, err := db.Open(filepath.Join(t.TempDir(), "products.db"))
+	assert.Nil(t, err)
+	defer conn.Close()
+	assert.Nil(t, db.Migrate(conn))
+
+	persistence := open(conn)
+	defer persistence.Close()
+	service := application.NewProductService(persistence)
+
+	var wg sync.WaitGroup
+	var failed atomic.Int64
+	for writer := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 50 {
+				if _, err := service.Create(context.Background(), fmt.Sprintf("Product %d-%d", writer, i), application.NewMoney(1000, application.DEFAULT_CURRENCY)); err != nil {
+					t.Log(err)
+					failed.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(0), failed.Load())
+	page, err := persistence.List(context.Background(), application.ProductQuery{Limit: application.MAX_PAGE_SIZE})
+	assert.Nil(t, err)
+	assert.Len(t, page.Products, application.MAX_PAGE_SIZE)
+}
+
+func TestProductDbConcurrentWriters(t *testing.T) {
+	saveConcurrently(t, func(conn *sql.DB) closablePersistence {
+		return db.NewProductDb(conn).WithOutbox()
 	})
 }
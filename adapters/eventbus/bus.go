@@ -0,0 +1,78 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+)
+
+type subscription struct {
+	handler    application.EventHandler
+	eventNames map[string]bool
+}
+
+func (s subscription) accepts(event application.DomainEvent) bool {
+	return len(s.eventNames) == 0 || s.eventNames[event.EventName()]
+}
+
+// Bus delivers events synchronously to every matching subscriber, in the order
+// they subscribed. A failing handler does not stop the others.
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions []subscription
+	OnError       func(event application.DomainEvent, err error)
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		OnError: func(event application.DomainEvent, err error) {
+			log.Printf("event %s for product %s: %v", event.EventName(), event.GetProductId(), err)
+		},
+	}
+}
+
+func (b *Bus) Subscribe(handler application.EventHandler, eventNames ...string) {
+	names := map[string]bool{}
+	for _, name := range eventNames {
+		names[name] = true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions = append(b.subscriptions, subscription{handler: handler, eventNames: names})
+}
+
+func (b *Bus) Publish(ctx context.Context, events ...application.DomainEvent) error {
+	b.mu.RLock()
+	subscriptions := b.subscriptions
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, event := range events {
+		for _, subscription := range subscriptions {
+			if !subscription.accepts(event) {
+				continue
+			}
+			if err := b.deliver(ctx, subscription.handler, event); err != nil {
+				if b.OnError != nil {
+					b.OnError(event, err)
+				}
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *Bus) deliver(ctx context.Context, handler application.EventHandler, event application.DomainEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return handler(ctx, event)
+}
@@ -0,0 +1,44 @@
+package eventbus_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/eventbus"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBusPublish(t *testing.T) {
+	bus := eventbus.NewBus()
+	var failures []string
+	bus.OnError = func(event application.DomainEvent, err error) {
+		failures = append(failures, event.EventName()+": "+err.Error())
+	}
+
+	var all, enabled []string
+	bus.Subscribe(func(ctx context.Context, event application.DomainEvent) error {
+		all = append(all, event.EventName())
+		return nil
+	})
+	bus.Subscribe(func(ctx context.Context, event application.DomainEvent) error {
+		enabled = append(enabled, event.GetProductId())
+		return nil
+	}, application.PRODUCT_ENABLED)
+	bus.Subscribe(func(ctx context.Context, event application.DomainEvent) error {
+		return errors.New("index is down")
+	}, application.PRODUCT_CREATED)
+	bus.Subscribe(func(ctx context.Context, event application.DomainEvent) error {
+		panic("boom")
+	}, application.PRODUCT_ENABLED)
+
+	product := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+	assert.Nil(t, product.Enable())
+
+	err := bus.Publish(context.Background(), product.Events()...)
+	assert.EqualError(t, err, "index is down\nhandler panicked: boom")
+	assert.Equal(t, []string{application.PRODUCT_CREATED, application.PRODUCT_ENABLED}, all)
+	assert.Equal(t, []string{product.GetId()}, enabled)
+	assert.Equal(t, []string{"product.created: index is down", "product.enabled: handler panicked: boom"}, failures)
+}
@@ -0,0 +1,157 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+)
+
+type resolver struct {
+	service application.ProductServiceInterface
+}
+
+type productResolver struct {
+	product application.ProductInterface
+}
+
+func (r *productResolver) ID() graphql.ID {
+	return graphql.ID(r.product.GetId())
+}
+
+func (r *productResolver) Name() string {
+	return r.product.GetName()
+}
+
+func (r *productResolver) Price() *moneyResolver {
+	return &moneyResolver{money: r.product.GetPrice()}
+}
+
+func (r *productResolver) Status() string {
+	return r.product.GetStatus()
+}
+
+func (r *productResolver) Version() int32 {
+	return int32(r.product.GetVersion())
+}
+
+type moneyResolver struct {
+	money application.Money
+}
+
+func (r *moneyResolver) Amount() string {
+	return r.money.Decimal()
+}
+
+func (r *moneyResolver) Currency() string {
+	return r.money.Currency
+}
+
+type productPageResolver struct {
+	page application.ProductPage
+}
+
+func (r *productPageResolver) Products() []*productResolver {
+	products := []*productResolver{}
+	for _, product := range r.page.Products {
+		products = append(products, &productResolver{product: product})
+	}
+	return products
+}
+
+func (r *productPageResolver) NextCursor() *string {
+	if r.page.NextCursor == "" {
+		return nil
+	}
+	return &r.page.NextCursor
+}
+
+func (r *resolver) Product(ctx context.Context, args struct{ ID graphql.ID }) (*productResolver, error) {
+	product, err := r.service.Get(ctx, string(args.ID))
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	return &productResolver{product: product}, nil
+}
+
+func (r *resolver) Products(ctx context.Context, args struct {
+	Status *string
+	Name   *string
+	SortBy *string
+	Desc   *bool
+	Limit  *int32
+	Cursor *string
+}) (*productPageResolver, error) {
+	query := application.ProductQuery{}
+	if args.Status != nil {
+		query.Status = *args.Status
+	}
+	if args.Name != nil {
+		query.Name = *args.Name
+	}
+	if args.SortBy != nil {
+		query.SortBy = *args.SortBy
+	}
+	if args.Desc != nil {
+		query.Desc = *args.Desc
+	}
+	if args.Limit != nil {
+		query.Limit = int(*args.Limit)
+	}
+	if args.Cursor != nil {
+		query.Cursor = *args.Cursor
+	}
+
+	page, err := r.service.List(ctx, query)
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	return &productPageResolver{page: page}, nil
+}
+
+func (r *resolver) CreateProduct(ctx context.Context, args struct {
+	Input struct {
+		Name     string
+		Price    string
+		Currency *string
+	}
+}) (*productResolver, error) {
+	currency := application.DEFAULT_CURRENCY
+	if args.Input.Currency != nil {
+		currency = *args.Input.Currency
+	}
+	price, err := application.ParseMoney(args.Input.Price, currency)
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+
+	product, err := r.service.Create(ctx, args.Input.Name, price)
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	return &productResolver{product: product}, nil
+}
+
+func (r *resolver) EnableProduct(ctx context.Context, args struct{ ID graphql.ID }) (*productResolver, error) {
+	product, err := r.service.Get(ctx, string(args.ID))
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	product, err = r.service.Enable(ctx, product)
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	return &productResolver{product: product}, nil
+}
+
+func (r *resolver) DisableProduct(ctx context.Context, args struct{ ID graphql.ID }) (*productResolver, error) {
+	product, err := r.service.Get(ctx, string(args.ID))
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	product, err = r.service.Disable(ctx, product)
+	if err != nil {
+		return nil, newResolverError(err)
+	}
+	return &productResolver{product: product}, nil
+}
@@ -0,0 +1,83 @@
+package graphql
+
+import (
+	_ "embed"
+	"errors"
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+)
+
+// DEFAULT_MAX_DEPTH fits the deepest query the schema allows, products {
+// products { price { amount } } }, with one level to spare.
+const DEFAULT_MAX_DEPTH = 5
+
+//go:embed schema.graphql
+var schema string
+
+type Server struct {
+	Service  application.ProductServiceInterface
+	MaxDepth int
+}
+
+func NewServer(service application.ProductServiceInterface) *Server {
+	return &Server{Service: service, MaxDepth: DEFAULT_MAX_DEPTH}
+}
+
+func (s *Server) Handler() http.Handler {
+	return &relay.Handler{
+		Schema: graphql.MustParseSchema(schema, &resolver{service: s.Service}, graphql.MaxDepth(s.MaxDepth)),
+	}
+}
+
+// resolverError exposes domain errors to clients as a code in the error
+// extensions, plus the invalid fields of a validation error.
+type resolverError struct {
+	err     error
+	message string
+	code    string
+	fields  []map[string]any
+}
+
+func (e *resolverError) Error() string {
+	return e.message
+}
+
+func (e *resolverError) Unwrap() error {
+	return e.err
+}
+
+func (e *resolverError) Extensions() map[string]any {
+	extensions := map[string]any{"code": e.code}
+	if len(e.fields) > 0 {
+		extensions["fields"] = e.fields
+	}
+	return extensions
+}
+
+func newResolverError(err error) error {
+	result := &resolverError{err: err, message: err.Error()}
+
+	var validationErr *application.ValidationError
+	switch {
+	case errors.Is(err, application.ErrProductNotFound):
+		result.code = "NOT_FOUND"
+	case errors.Is(err, application.ErrConcurrentModification):
+		result.code = "CONCURRENT_MODIFICATION"
+	case errors.Is(err, application.ErrInvalidTransition):
+		result.code = "INVALID_TRANSITION"
+	case errors.As(err, &validationErr):
+		result.code = "VALIDATION_FAILED"
+		for _, field := range validationErr.Fields {
+			result.fields = append(result.fields, map[string]any{"field": field.Field, "message": field.Message})
+		}
+	case errors.Is(err, application.ErrInvalidPrice):
+		result.code = "VALIDATION_FAILED"
+	default:
+		result.code = "INTERNAL"
+		result.message = "internal error"
+	}
+	return result
+}
@@ -0,0 +1,134 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/graphql"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	price := application.NewMoney(1999, application.DEFAULT_CURRENCY)
+	product := &application.Product{Id: "1", Name: "Product 1", Price: price, Status: application.DISABLED, Version: 2}
+	enabled := &application.Product{Id: "1", Name: "Product 1", Price: price, Status: application.ENABLED, Version: 3}
+	conflicted := application.NewProduct("Conflicted", price)
+	locked := application.NewProduct("Locked", price)
+
+	serviceMock := mock.NewMockProductServiceInterface(ctrl)
+	serviceMock.EXPECT().Get(gomock.Any(), "1").Return(product, nil).AnyTimes()
+	serviceMock.EXPECT().Get(gomock.Any(), "missing").Return(nil, application.ErrProductNotFound).AnyTimes()
+	serviceMock.EXPECT().Get(gomock.Any(), "conflict").Return(conflicted, nil).AnyTimes()
+	serviceMock.EXPECT().Get(gomock.Any(), "locked").Return(locked, nil).AnyTimes()
+	serviceMock.EXPECT().Get(gomock.Any(), "broken").Return(nil, errors.New("database is locked")).AnyTimes()
+	serviceMock.EXPECT().List(gomock.Any(), application.ProductQuery{Status: application.ENABLED, SortBy: "price", Limit: 1}).Return(application.ProductPage{
+		Products:   []application.ProductInterface{enabled},
+		NextCursor: "next",
+	}, nil).AnyTimes()
+	serviceMock.EXPECT().Create(gomock.Any(), "Product 1", price).Return(product, nil).AnyTimes()
+	serviceMock.EXPECT().Create(gomock.Any(), "", gomock.Any()).Return(nil, application.NewValidationError("name", "Name: non zero value required", nil)).AnyTimes()
+	serviceMock.EXPECT().Enable(gomock.Any(), product).Return(enabled, nil).AnyTimes()
+	serviceMock.EXPECT().Enable(gomock.Any(), conflicted).Return(nil, application.ErrConcurrentModification).AnyTimes()
+	serviceMock.EXPECT().Disable(gomock.Any(), locked).Return(nil, &application.TransitionError{From: application.ENABLED, To: application.DISABLED, Reason: "The price must be zero to disable the product"}).AnyTimes()
+
+	handler := graphql.NewServer(serviceMock).Handler()
+
+	tests := []struct {
+		testName string
+		query    string
+		expected string
+	}{
+		{
+			testName: "Success - Get",
+			query:    `{ product(id: "1") { id name price { amount currency } status version } }`,
+			expected: `{"data":{"product":{"id":"1","name":"Product 1","price":{"amount":"19.99","currency":"BRL"},"status":"disabled","version":2}}}`,
+		},
+		{
+			testName: "Success - Select only some fields",
+			query:    `{ product(id: "1") { name } }`,
+			expected: `{"data":{"product":{"name":"Product 1"}}}`,
+		},
+		{
+			testName: "Success - List",
+			query:    `{ products(status: "enabled", sortBy: "price", limit: 1) { products { id status } nextCursor } }`,
+			expected: `{"data":{"products":{"products":[{"id":"1","status":"enabled"}],"nextCursor":"next"}}}`,
+		},
+		{
+			testName: "Success - Create",
+			query:    `mutation { createProduct(input: {name: "Product 1", price: "19.99"}) { id } }`,
+			expected: `{"data":{"createProduct":{"id":"1"}}}`,
+		},
+		{
+			testName: "Success - Enable",
+			query:    `mutation { enableProduct(id: "1") { status version } }`,
+			expected: `{"data":{"enableProduct":{"status":"enabled","version":3}}}`,
+		},
+		{
+			testName: "Error - Get a product that does not exist",
+			query:    `{ product(id: "missing") { id } }`,
+			expected: `{"errors":[{"message":"Product not found","path":["product"],"extensions":{"code":"NOT_FOUND"}}],"data":{"product":null}}`,
+		},
+		{
+			testName: "Error - Create a product without a name",
+			query:    `mutation { createProduct(input: {name: "", price: "19.99"}) { id } }`,
+			expected: `{"errors":[{"message":"Name: non zero value required","path":["createProduct"],"extensions":{"code":"VALIDATION_FAILED","fields":[{"field":"name","message":"Name: non zero value required"}]}}],"data":null}`,
+		},
+		{
+			testName: "Error - Create a product with an invalid price",
+			query:    `mutation { createProduct(input: {name: "Product 1", price: "19.999"}) { id } }`,
+			expected: `{"errors":[{"message":"The price must have at most 2 decimal places for BRL","path":["createProduct"],"extensions":{"code":"VALIDATION_FAILED","fields":[{"field":"price","message":"The price must have at most 2 decimal places for BRL"}]}}],"data":null}`,
+		},
+		{
+			testName: "Error - Enable a product changed by another request",
+			query:    `mutation { enableProduct(id: "conflict") { id } }`,
+			expected: `{"errors":[{"message":"The product was modified by another operation","path":["enableProduct"],"extensions":{"code":"CONCURRENT_MODIFICATION"}}],"data":null}`,
+		},
+		{
+			testName: "Error - Disable a priced product",
+			query:    `mutation { disableProduct(id: "locked") { id } }`,
+			expected: `{"errors":[{"message":"The price must be zero to disable the product","path":["disableProduct"],"extensions":{"code":"INVALID_TRANSITION"}}],"data":null}`,
+		},
+		{
+			testName: "Error - Hide unexpected errors",
+			query:    `{ product(id: "broken") { id } }`,
+			expected: `{"errors":[{"message":"internal error","path":["product"],"extensions":{"code":"INTERNAL"}}],"data":{"product":null}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			assert.JSONEq(t, tt.expected, execute(t, handler, tt.query))
+		})
+	}
+}
+
+func TestServerMaxDepth(t *testing.T) {
+	server := graphql.NewServer(nil)
+	server.MaxDepth = 2
+
+	response := execute(t, server.Handler(), `{ product(id: "1") { price { amount } } }`)
+	assert.Contains(t, response, `Field \"amount\" has depth 3 that exceeds max depth 2`)
+	assert.NotContains(t, response, `"data"`)
+}
+
+func execute(t *testing.T, handler http.Handler, query string) string {
+	body, err := json.Marshal(map[string]string{"query": query})
+	assert.Nil(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	return response.Body.String()
+}
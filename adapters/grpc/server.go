@@ -0,0 +1,214 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/grpc/pb"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+type ProductServer struct {
+	pb.UnimplementedProductServiceServer
+	Service application.ProductServiceInterface
+	// Reflection lets tools such as grpcurl discover the service.
+	Reflection bool
+	// TrustedProxies are the addresses allowed to name the actor of a
+	// change in the x-actor metadata, after authenticating the user.
+	TrustedProxies []netip.Prefix
+}
+
+func NewProductServer(service application.ProductServiceInterface) *ProductServer {
+	return &ProductServer{Service: service}
+}
+
+func (s *ProductServer) NewGRPCServer() *grpc.Server {
+	server := grpc.NewServer(grpc.UnaryInterceptor(s.unaryActor), grpc.StreamInterceptor(s.streamActor))
+	pb.RegisterProductServiceServer(server, s)
+	if s.Reflection {
+		reflection.Register(server)
+	}
+	return server
+}
+
+func (s *ProductServer) Serve(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	server := s.NewGRPCServer()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		server.GracefulStop()
+		return <-errs
+	}
+}
+
+func newProduct(product application.ProductInterface) *pb.Product {
+	return &pb.Product{
+		Id:      product.GetId(),
+		Name:    product.GetName(),
+		Price:   &pb.Money{Amount: product.GetPrice().Amount, Currency: product.GetPrice().Currency},
+		Status:  product.GetStatus(),
+		Version: int32(product.GetVersion()),
+	}
+}
+
+func (s *ProductServer) GetProduct(ctx context.Context, request *pb.GetProductRequest) (*pb.Product, error) {
+	product, err := s.Service.Get(ctx, request.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return newProduct(product), nil
+}
+
+func (s *ProductServer) CreateProduct(ctx context.Context, request *pb.CreateProductRequest) (*pb.Product, error) {
+	currency := request.GetPrice().GetCurrency()
+	if currency == "" {
+		currency = application.DEFAULT_CURRENCY
+	}
+
+	product, err := s.Service.Create(ctx, request.GetName(), application.NewMoney(request.GetPrice().GetAmount(), currency))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return newProduct(product), nil
+}
+
+func (s *ProductServer) EnableProduct(ctx context.Context, request *pb.EnableProductRequest) (*pb.Product, error) {
+	product, err := s.Service.Get(ctx, request.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	product, err = s.Service.Enable(ctx, product)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return newProduct(product), nil
+}
+
+func (s *ProductServer) DisableProduct(ctx context.Context, request *pb.DisableProductRequest) (*pb.Product, error) {
+	product, err := s.Service.Get(ctx, request.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	product, err = s.Service.Disable(ctx, product)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return newProduct(product), nil
+}
+
+func (s *ProductServer) ListProducts(request *pb.ListProductsRequest, stream pb.ProductService_ListProductsServer) error {
+	query := application.ProductQuery{
+		Status: request.GetStatus(),
+		Name:   request.GetName(),
+		SortBy: request.GetSortBy(),
+		Desc:   request.GetDesc(),
+		Limit:  application.MAX_PAGE_SIZE,
+	}
+	for {
+		page, err := s.Service.List(stream.Context(), query)
+		if err != nil {
+			return toStatus(err)
+		}
+		for _, product := range page.Products {
+			if err := stream.Send(newProduct(product)); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+func toStatus(err error) error {
+	var validationErr *application.ValidationError
+	switch {
+	case errors.Is(err, application.ErrProductNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, application.ErrConcurrentModification):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, application.ErrInvalidTransition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.As(err, &validationErr):
+		badRequest := &errdetails.BadRequest{}
+		for _, field := range validationErr.Fields {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: field.Field, Description: field.Message})
+		}
+		result, detailsErr := status.New(codes.InvalidArgument, err.Error()).WithDetails(badRequest)
+		if detailsErr != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		return result.Err()
+	case errors.Is(err, application.ErrInvalidPrice):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// Changes made through the service are attributed to the x-actor metadata.
+// The metadata is not authenticated, so it is ignored unless a trusted proxy
+// sent the call.
+func (s *ProductServer) withActor(ctx context.Context) context.Context {
+	if values := metadata.ValueFromIncomingContext(ctx, "x-actor"); len(values) > 0 && values[0] != "" && s.trusted(ctx) {
+		return application.WithActor(ctx, values[0])
+	}
+	return ctx
+}
+
+func (s *ProductServer) trusted(ctx context.Context) bool {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	addr, err := netip.ParseAddrPort(p.Addr.String())
+	if err != nil {
+		return false
+	}
+	for _, prefix := range s.TrustedProxies {
+		if prefix.Contains(addr.Addr().Unmap()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ProductServer) unaryActor(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(s.withActor(ctx), request)
+}
+
+type actorStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s actorStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *ProductServer) streamActor(server any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(server, actorStream{ServerStream: stream, ctx: s.withActor(stream.Context())})
+}
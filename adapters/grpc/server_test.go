@@ -0,0 +1,270 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	productgrpc "github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/grpc"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/grpc/pb"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application/mock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func dial(t *testing.T, server *productgrpc.ProductServer) *grpc.ClientConn {
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := server.NewGRPCServer()
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestProductServer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	price := application.NewMoney(1999, application.DEFAULT_CURRENCY)
+	product := &application.Product{Id: "1", Name: "Product 1", Price: price, Status: application.DISABLED, Version: 2}
+	enabled := &application.Product{Id: "1", Name: "Product 1", Price: price, Status: application.ENABLED, Version: 3}
+	conflicted := application.NewProduct("Conflicted", price)
+	locked := application.NewProduct("Locked", price)
+
+	serviceMock := mock.NewMockProductServiceInterface(ctrl)
+	serviceMock.EXPECT().Get(gomock.Any(), "1").Return(product, nil).AnyTimes()
+	serviceMock.EXPECT().Get(gomock.Any(), "missing").Return(nil, application.ErrProductNotFound).AnyTimes()
+	serviceMock.EXPECT().Get(gomock.Any(), "conflict").Return(conflicted, nil).AnyTimes()
+	serviceMock.EXPECT().Get(gomock.Any(), "locked").Return(locked, nil).AnyTimes()
+	serviceMock.EXPECT().Get(gomock.Any(), "broken").Return(nil, errors.New("database is locked")).AnyTimes()
+	serviceMock.EXPECT().Create(gomock.Any(), "Product 1", price).Return(product, nil).AnyTimes()
+	serviceMock.EXPECT().Create(gomock.Any(), "", gomock.Any()).Return(nil, application.NewValidationError("name", "Name: non zero value required", nil)).AnyTimes()
+	serviceMock.EXPECT().Enable(gomock.Any(), product).Return(enabled, nil).AnyTimes()
+	serviceMock.EXPECT().Enable(gomock.Any(), conflicted).Return(nil, application.ErrConcurrentModification).AnyTimes()
+	serviceMock.EXPECT().Disable(gomock.Any(), locked).Return(nil, &application.TransitionError{From: application.ENABLED, To: application.DISABLED, Reason: "The price must be zero to disable the product"}).AnyTimes()
+
+	client := pb.NewProductServiceClient(dial(t, productgrpc.NewProductServer(serviceMock)))
+	ctx := context.Background()
+
+	t.Run("Success - Get", func(t *testing.T) {
+		result, err := client.GetProduct(ctx, &pb.GetProductRequest{Id: "1"})
+		assert.Nil(t, err)
+		assert.Equal(t, "Product 1", result.GetName())
+		assert.Equal(t, int64(1999), result.GetPrice().GetAmount())
+		assert.Equal(t, "BRL", result.GetPrice().GetCurrency())
+		assert.Equal(t, application.DISABLED, result.GetStatus())
+		assert.Equal(t, int32(2), result.GetVersion())
+	})
+
+	t.Run("Success - Create with the default currency", func(t *testing.T) {
+		result, err := client.CreateProduct(ctx, &pb.CreateProductRequest{Name: "Product 1", Price: &pb.Money{Amount: 1999}})
+		assert.Nil(t, err)
+		assert.Equal(t, "1", result.GetId())
+	})
+
+	t.Run("Success - Enable", func(t *testing.T) {
+		result, err := client.EnableProduct(ctx, &pb.EnableProductRequest{Id: "1"})
+		assert.Nil(t, err)
+		assert.Equal(t, application.ENABLED, result.GetStatus())
+		assert.Equal(t, int32(3), result.GetVersion())
+	})
+
+	t.Run("Error - Create an invalid product", func(t *testing.T) {
+		_, err := client.CreateProduct(ctx, &pb.CreateProductRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		details := status.Convert(err).Details()
+		assert.Len(t, details, 1)
+		badRequest, ok := details[0].(*errdetails.BadRequest)
+		assert.True(t, ok)
+		assert.Equal(t, "name", badRequest.GetFieldViolations()[0].GetField())
+		assert.Equal(t, "Name: non zero value required", badRequest.GetFieldViolations()[0].GetDescription())
+	})
+
+	errorTests := []struct {
+		testName string
+		call     func() error
+		code     codes.Code
+	}{
+		{
+			testName: "Error - Get a product that does not exist",
+			call: func() error {
+				_, err := client.GetProduct(ctx, &pb.GetProductRequest{Id: "missing"})
+				return err
+			},
+			code: codes.NotFound,
+		},
+		{
+			testName: "Error - Enable a product changed by another request",
+			call: func() error {
+				_, err := client.EnableProduct(ctx, &pb.EnableProductRequest{Id: "conflict"})
+				return err
+			},
+			code: codes.Aborted,
+		},
+		{
+			testName: "Error - Disable a priced product",
+			call: func() error {
+				_, err := client.DisableProduct(ctx, &pb.DisableProductRequest{Id: "locked"})
+				return err
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			testName: "Error - Disable a product that does not exist",
+			call: func() error {
+				_, err := client.DisableProduct(ctx, &pb.DisableProductRequest{Id: "missing"})
+				return err
+			},
+			code: codes.NotFound,
+		},
+		{
+			testName: "Error - Hide unexpected errors",
+			call: func() error {
+				_, err := client.GetProduct(ctx, &pb.GetProductRequest{Id: "broken"})
+				return err
+			},
+			code: codes.Internal,
+		},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.testName, func(t *testing.T) {
+			err := tt.call()
+			assert.Equal(t, tt.code, status.Code(err))
+			assert.NotContains(t, err.Error(), "database is locked")
+		})
+	}
+}
+
+func TestProductServerListProducts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	price := application.NewMoney(1000, application.DEFAULT_CURRENCY)
+	first := &application.Product{Id: "1", Name: "Product 1", Price: price, Status: application.ENABLED, Version: 1}
+	second := &application.Product{Id: "2", Name: "Product 2", Price: price, Status: application.ENABLED, Version: 1}
+
+	query := application.ProductQuery{Status: application.ENABLED, SortBy: "name", Limit: application.MAX_PAGE_SIZE}
+	serviceMock := mock.NewMockProductServiceInterface(ctrl)
+	serviceMock.EXPECT().List(gomock.Any(), query).Return(application.ProductPage{
+		Products:   []application.ProductInterface{first},
+		NextCursor: "next",
+	}, nil)
+	query.Cursor = "next"
+	serviceMock.EXPECT().List(gomock.Any(), query).Return(application.ProductPage{
+		Products: []application.ProductInterface{second},
+	}, nil)
+	serviceMock.EXPECT().List(gomock.Any(), application.ProductQuery{Status: "invalid", Limit: application.MAX_PAGE_SIZE}).Return(application.ProductPage{}, application.NewValidationError("status", "The status filter must be enabled, disabled or archived", nil))
+
+	client := pb.NewProductServiceClient(dial(t, productgrpc.NewProductServer(serviceMock)))
+
+	t.Run("Success - Stream every page", func(t *testing.T) {
+		stream, err := client.ListProducts(context.Background(), &pb.ListProductsRequest{Status: application.ENABLED, SortBy: "name"})
+		assert.Nil(t, err)
+
+		var ids []string
+		for {
+			product, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			assert.Nil(t, err)
+			ids = append(ids, product.GetId())
+		}
+		assert.Equal(t, []string{"1", "2"}, ids)
+	})
+
+	t.Run("Error - Invalid filter", func(t *testing.T) {
+		stream, err := client.ListProducts(context.Background(), &pb.ListProductsRequest{Status: "invalid"})
+		assert.Nil(t, err)
+		_, err = stream.Recv()
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestProductServerActor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var actor string
+	serviceMock := mock.NewMockProductServiceInterface(ctrl)
+	serviceMock.EXPECT().Get(gomock.Any(), "1").DoAndReturn(func(ctx context.Context, id string) (application.ProductInterface, error) {
+		actor = application.ActorFromContext(ctx)
+		return nil, application.ErrProductNotFound
+	}).Times(3)
+
+	server := productgrpc.NewProductServer(serviceMock)
+	server.TrustedProxies = []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	grpcServer := server.NewGRPCServer()
+	go grpcServer.Serve(listener)
+	defer grpcServer.Stop()
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err)
+	defer conn.Close()
+	client := pb.NewProductServiceClient(conn)
+
+	_, _ = client.GetProduct(metadata.AppendToOutgoingContext(context.Background(), "x-actor", "alice"), &pb.GetProductRequest{Id: "1"})
+	assert.Equal(t, "alice", actor)
+
+	_, _ = client.GetProduct(context.Background(), &pb.GetProductRequest{Id: "1"})
+	assert.Equal(t, application.UNKNOWN_ACTOR, actor)
+
+	// Calls from addresses that are not trusted can not name the actor.
+	untrusted := pb.NewProductServiceClient(dial(t, server))
+	_, _ = untrusted.GetProduct(metadata.AppendToOutgoingContext(context.Background(), "x-actor", "alice"), &pb.GetProductRequest{Id: "1"})
+	assert.Equal(t, application.UNKNOWN_ACTOR, actor)
+}
+
+func TestProductServerReflection(t *testing.T) {
+	listServices := func(t *testing.T, server *productgrpc.ProductServer) (*reflectionpb.ServerReflectionResponse, error) {
+		client := reflectionpb.NewServerReflectionClient(dial(t, server))
+		stream, err := client.ServerReflectionInfo(context.Background())
+		assert.Nil(t, err)
+		err = stream.Send(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+		})
+		assert.Nil(t, err)
+		return stream.Recv()
+	}
+
+	t.Run("Success - List the services when enabled", func(t *testing.T) {
+		server := productgrpc.NewProductServer(nil)
+		server.Reflection = true
+
+		response, err := listServices(t, server)
+		assert.Nil(t, err)
+		var services []string
+		for _, service := range response.GetListServicesResponse().GetService() {
+			services = append(services, service.GetName())
+		}
+		assert.Contains(t, services, "product.v1.ProductService")
+	})
+
+	t.Run("Error - Reflection is off by default", func(t *testing.T) {
+		_, err := listServices(t, productgrpc.NewProductServer(nil))
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
@@ -0,0 +1,215 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+)
+
+// ProductMemory keeps the catalog in process memory with the same semantics
+// as db.ProductDb. A transaction holds the store exclusively until it ends, so
+// operations must go through the persistence it hands out meanwhile.
+type ProductMemory struct {
+	mu      *sync.RWMutex
+	catalog *catalog
+	inTx    bool
+	// rollback undoes the changes saves made to products when the
+	// transaction does not commit.
+	rollback *[]func()
+}
+
+type catalog struct {
+	products map[string]application.Product
+	history  map[string][]application.ProductChange
+}
+
+func NewProductMemory() *ProductMemory {
+	return &ProductMemory{
+		mu: &sync.RWMutex{},
+		catalog: &catalog{
+			products: map[string]application.Product{},
+			history:  map[string][]application.ProductChange{},
+		},
+	}
+}
+
+func (c *catalog) clone() *catalog {
+	history := make(map[string][]application.ProductChange, len(c.history))
+	for id, changes := range c.history {
+		history[id] = append([]application.ProductChange(nil), changes...)
+	}
+	return &catalog{products: maps.Clone(c.products), history: history}
+}
+
+func (p *ProductMemory) read(fn func(catalog *catalog)) {
+	if !p.inTx {
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+	}
+	fn(p.catalog)
+}
+
+func (p *ProductMemory) write(fn func(catalog *catalog) error) error {
+	if p.inTx {
+		return fn(p.catalog)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return fn(p.catalog)
+}
+
+// Calls made while a transaction is already open join it instead of starting a new one.
+func (p *ProductMemory) WithinTransaction(ctx context.Context, fn func(ctx context.Context, persistence application.ProductPersistenceInterface) error) error {
+	if p.inTx {
+		return fn(ctx, p)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var rollback []func()
+	tx := &ProductMemory{mu: p.mu, catalog: p.catalog.clone(), inTx: true, rollback: &rollback}
+	err := fn(ctx, tx)
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		for i := len(rollback) - 1; i >= 0; i-- {
+			rollback[i]()
+		}
+		return err
+	}
+	p.catalog = tx.catalog
+	return nil
+}
+
+// Products are stored and returned as copies, so callers can not change the
+// catalog without saving.
+func copyOf(product application.ProductInterface) *application.Product {
+	stored := &application.Product{
+		Id:      product.GetId(),
+		Name:    product.GetName(),
+		Price:   product.GetPrice(),
+		Status:  product.GetStatus(),
+		Version: product.GetVersion(),
+	}
+	if deletedAt := product.GetDeletedAt(); deletedAt != nil {
+		at := *deletedAt
+		stored.DeletedAt = &at
+	}
+	return stored
+}
+
+func (p *ProductMemory) Get(ctx context.Context, id string) (application.ProductInterface, error) {
+	return p.get(ctx, id, false)
+}
+
+func (p *ProductMemory) GetArchived(ctx context.Context, id string) (application.ProductInterface, error) {
+	return p.get(ctx, id, true)
+}
+
+func (p *ProductMemory) get(ctx context.Context, id string, archived bool) (application.ProductInterface, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var product application.Product
+	var ok bool
+	p.read(func(catalog *catalog) {
+		product, ok = catalog.products[id]
+	})
+	if !ok || (product.DeletedAt != nil) != archived {
+		return nil, fmt.Errorf("%w: %s", application.ErrProductNotFound, id)
+	}
+	return copyOf(&product), nil
+}
+
+func (p *ProductMemory) List(ctx context.Context, query application.ProductQuery) (application.ProductPage, error) {
+	if err := ctx.Err(); err != nil {
+		return application.ProductPage{}, err
+	}
+
+	var products []application.ProductInterface
+	p.read(func(catalog *catalog) {
+		for _, product := range catalog.products {
+			products = append(products, copyOf(&product))
+		}
+	})
+	return query.Apply(products)
+}
+
+func (p *ProductMemory) History(ctx context.Context, id string) ([]application.ProductChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var changes []application.ProductChange
+	var exists bool
+	p.read(func(catalog *catalog) {
+		changes = append(changes, catalog.history[id]...)
+		_, exists = catalog.products[id]
+	})
+	if len(changes) == 0 && !exists {
+		return nil, fmt.Errorf("%w: %s", application.ErrProductNotFound, id)
+	}
+	return changes, nil
+}
+
+// A product that claims a version but is missing from the catalog was purged
+// by another operation.
+func (p *ProductMemory) Save(ctx context.Context, product application.ProductInterface) (application.ProductInterface, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var version int
+	err := p.write(func(catalog *catalog) error {
+		var previous application.ProductInterface
+		stored, ok := catalog.products[product.GetId()]
+		if ok {
+			previous = &stored
+		}
+		if (ok && stored.Version != product.GetVersion()) || (!ok && product.GetVersion() != 0) {
+			return fmt.Errorf("%w: %s", application.ErrConcurrentModification, product.GetId())
+		}
+
+		version = product.GetVersion() + 1
+		saved := copyOf(product)
+		saved.Version = version
+		catalog.products[product.GetId()] = *saved
+		changes := application.DiffProduct(previous, product, version, application.ActorFromContext(ctx), time.Now().UTC())
+		catalog.history[product.GetId()] = append(catalog.history[product.GetId()], changes...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	previous := product.GetVersion()
+	product.SetVersion(version)
+	if p.inTx {
+		*p.rollback = append(*p.rollback, func() {
+			product.SetVersion(previous)
+		})
+	}
+	return product, nil
+}
+
+func (p *ProductMemory) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return p.write(func(catalog *catalog) error {
+		if _, ok := catalog.products[id]; !ok {
+			return fmt.Errorf("%w: %s", application.ErrProductNotFound, id)
+		}
+		delete(catalog.products, id)
+		delete(catalog.history, id)
+		return nil
+	})
+}
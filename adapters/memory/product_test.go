@@ -0,0 +1,100 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/memory"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application/persistencetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductMemoryConformance(t *testing.T) {
+	persistencetest.Run(t, func(t *testing.T) application.ProductPersistenceInterface {
+		return memory.NewProductMemory()
+	})
+}
+
+func TestProductMemoryCopies(t *testing.T) {
+	productMemory := memory.NewProductMemory()
+	product := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+	_, err := productMemory.Save(context.Background(), product)
+	assert.Nil(t, err)
+
+	assert.Nil(t, product.ChangeName("Unsaved"))
+	stored, err := productMemory.Get(context.Background(), product.GetId())
+	assert.Nil(t, err)
+	assert.Equal(t, "Product 1", stored.GetName())
+
+	assert.Nil(t, stored.ChangeName("Also unsaved"))
+	stored, err = productMemory.Get(context.Background(), product.GetId())
+	assert.Nil(t, err)
+	assert.Equal(t, "Product 1", stored.GetName())
+}
+
+func TestProductMemoryWithinTransaction(t *testing.T) {
+	productMemory := memory.NewProductMemory()
+	first := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+	second := application.NewProduct("Product 2", application.NewMoney(2000, application.DEFAULT_CURRENCY))
+
+	t.Run("Error - Roll back every save", func(t *testing.T) {
+		err := productMemory.WithinTransaction(context.Background(), func(ctx context.Context, persistence application.ProductPersistenceInterface) error {
+			if _, err := persistence.Save(ctx, first); err != nil {
+				return err
+			}
+			if _, err := persistence.Get(ctx, first.GetId()); err != nil {
+				return err
+			}
+			return errors.New("abort")
+		})
+		assert.EqualError(t, err, "abort")
+
+		_, err = productMemory.Get(context.Background(), first.GetId())
+		assert.ErrorIs(t, err, application.ErrProductNotFound)
+		_, err = productMemory.History(context.Background(), first.GetId())
+		assert.ErrorIs(t, err, application.ErrProductNotFound)
+		assert.Equal(t, 0, first.GetVersion())
+	})
+
+	t.Run("Success - Commit every save, joining nested transactions", func(t *testing.T) {
+		err := productMemory.WithinTransaction(context.Background(), func(ctx context.Context, persistence application.ProductPersistenceInterface) error {
+			if _, err := persistence.Save(ctx, first); err != nil {
+				return err
+			}
+			return persistence.(application.ProductTransactionInterface).WithinTransaction(ctx, func(ctx context.Context, persistence application.ProductPersistenceInterface) error {
+				_, err := persistence.Save(ctx, second)
+				return err
+			})
+		})
+		assert.Nil(t, err)
+
+		page, err := productMemory.List(context.Background(), application.ProductQuery{})
+		assert.Nil(t, err)
+		assert.Len(t, page.Products, 2)
+		assert.Equal(t, 1, first.GetVersion())
+	})
+
+	t.Run("Success - Retry a change after the service rolled it back", func(t *testing.T) {
+		service := application.NewProductService(productMemory)
+		first.ClearEvents()
+		err := service.WithinTransaction(context.Background(), func(ctx context.Context, service application.ProductServiceInterface) error {
+			if _, err := service.Enable(ctx, first); err != nil {
+				return err
+			}
+			return errors.New("abort")
+		})
+		assert.EqualError(t, err, "abort")
+		assert.Equal(t, 1, first.GetVersion())
+		assert.Len(t, first.Events(), 1)
+
+		result, err := service.ChangeName(context.Background(), first, "Product 1b")
+		assert.Nil(t, err)
+		assert.Equal(t, 2, result.GetVersion())
+		stored, err := productMemory.Get(context.Background(), first.GetId())
+		assert.Nil(t, err)
+		assert.Equal(t, application.ENABLED, stored.GetStatus())
+		assert.Equal(t, "Product 1b", stored.GetName())
+	})
+}
@@ -0,0 +1,381 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+)
+
+// Lines of the list view that are not product rows.
+const chromeHeight = 7
+
+type view int
+
+const (
+	listView view = iota
+	detailView
+)
+
+type productsLoaded struct {
+	page application.ProductPage
+	err  error
+}
+
+type productSaved struct {
+	product application.ProductInterface
+	err     error
+}
+
+// Model browses and edits products through the service. It only draws ASCII
+// text and the cursor does not blink, so it works in plain terminals and over
+// SSH.
+type Model struct {
+	ctx     context.Context
+	service application.ProductServiceInterface
+
+	view     view
+	query    application.ProductQuery
+	previous []string
+	page     application.ProductPage
+	selected int
+
+	search    textinput.Model
+	searching bool
+	price     textinput.Model
+	editing   bool
+
+	status string
+	err    error
+}
+
+func NewModel(ctx context.Context, service application.ProductServiceInterface) Model {
+	return Model{
+		ctx:     ctx,
+		service: service,
+		query:   application.ProductQuery{Limit: application.DEFAULT_PAGE_SIZE},
+		search:  newInput("Search: "),
+		price:   newInput("New price: "),
+	}
+}
+
+func newInput(prompt string) textinput.Model {
+	input := textinput.New()
+	input.Prompt = prompt
+	input.Cursor.SetMode(cursor.CursorStatic)
+	return input
+}
+
+// Run takes over the terminal until the user quits or ctx is done.
+func Run(ctx context.Context, service application.ProductServiceInterface, in io.Reader, out io.Writer) error {
+	program := tea.NewProgram(NewModel(ctx, service), tea.WithContext(ctx), tea.WithInput(in), tea.WithOutput(out), tea.WithAltScreen())
+	_, err := program.Run()
+	if errors.Is(err, tea.ErrProgramKilled) {
+		return nil
+	}
+	return err
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.load()
+}
+
+func (m Model) load() tea.Cmd {
+	query := m.query
+	return func() tea.Msg {
+		page, err := m.service.List(m.ctx, query)
+		return productsLoaded{page: page, err: err}
+	}
+}
+
+// The service changes the product it is given before saving it, so commands
+// get a copy: the list keeps showing the stored product when the save fails
+// and View never reads a product while it changes.
+func copyOf(product application.ProductInterface) *application.Product {
+	copied := &application.Product{
+		Id:      product.GetId(),
+		Name:    product.GetName(),
+		Price:   product.GetPrice(),
+		Status:  product.GetStatus(),
+		Version: product.GetVersion(),
+	}
+	if deletedAt := product.GetDeletedAt(); deletedAt != nil {
+		at := *deletedAt
+		copied.DeletedAt = &at
+	}
+	return copied
+}
+
+func (m Model) toggle(product application.ProductInterface) tea.Cmd {
+	product = copyOf(product)
+	return func() tea.Msg {
+		if product.GetStatus() == application.ENABLED {
+			saved, err := m.service.Disable(m.ctx, product)
+			return productSaved{product: saved, err: err}
+		}
+		saved, err := m.service.Enable(m.ctx, product)
+		return productSaved{product: saved, err: err}
+	}
+}
+
+func (m Model) changePrice(product application.ProductInterface, price application.Money) tea.Cmd {
+	product = copyOf(product)
+	return func() tea.Msg {
+		saved, err := m.service.ChangePrice(m.ctx, product, price)
+		return productSaved{product: saved, err: err}
+	}
+}
+
+func (m Model) current() application.ProductInterface {
+	if m.selected < 0 || m.selected >= len(m.page.Products) {
+		return nil
+	}
+	return m.page.Products[m.selected]
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		limit := max(msg.Height-chromeHeight, 1)
+		if limit == m.query.Limit {
+			return m, nil
+		}
+		m.query.Limit = limit
+		return m, m.load()
+
+	case productsLoaded:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.page = msg.page
+		m.selected = min(m.selected, max(len(m.page.Products)-1, 0))
+		return m, nil
+
+	case productSaved:
+		if msg.err != nil {
+			m.err = msg.err
+			// Another operation changed the product, so show its latest state.
+			if errors.Is(msg.err, application.ErrConcurrentModification) {
+				m.editing = false
+				return m, m.load()
+			}
+			return m, nil
+		}
+		for i, product := range m.page.Products {
+			if product.GetId() == msg.product.GetId() {
+				m.page.Products[i] = msg.product
+			}
+		}
+		m.editing = false
+		m.status = fmt.Sprintf("Saved %s", msg.product.GetName())
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		m.status = ""
+		switch {
+		case m.searching:
+			return m.updateSearch(msg)
+		case m.editing:
+			return m.updatePrice(msg)
+		default:
+			m.err = nil
+			return m.updateKeys(msg)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.searching = false
+		m.search.Blur()
+		m.query.Name = strings.TrimSpace(m.search.Value())
+		m.query.Cursor = ""
+		m.previous = nil
+		m.selected = 0
+		return m, m.load()
+	case tea.KeyEsc:
+		m.searching = false
+		m.search.Blur()
+		m.search.SetValue(m.query.Name)
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.search, cmd = m.search.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updatePrice(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	product := m.current()
+	if product == nil {
+		m.editing = false
+		return m, nil
+	}
+	switch msg.Type {
+	case tea.KeyEnter:
+		price, err := application.ParseMoney(m.price.Value(), product.GetPrice().Currency)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.err = nil
+		return m, m.changePrice(product, price)
+	case tea.KeyEsc:
+		m.editing = false
+		m.err = nil
+		m.price.Blur()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.price, cmd = m.price.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	product := m.current()
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "up", "k":
+		if m.view == listView && m.selected > 0 {
+			m.selected--
+		}
+	case "down", "j":
+		if m.view == listView && m.selected < len(m.page.Products)-1 {
+			m.selected++
+		}
+	case "enter":
+		if product != nil {
+			m.view = detailView
+		}
+	case "esc", "backspace":
+		m.view = listView
+	case "/":
+		m.view = listView
+		m.searching = true
+		return m, m.search.Focus()
+	case "e":
+		if product != nil {
+			return m, m.toggle(product)
+		}
+	case "p":
+		if product != nil {
+			m.editing = true
+			m.price.Prompt = fmt.Sprintf("New price (%s): ", product.GetPrice().Currency)
+			m.price.SetValue(product.GetPrice().Decimal())
+			m.price.CursorEnd()
+			return m, m.price.Focus()
+		}
+	case "n", "right":
+		if m.view == listView && m.page.NextCursor != "" {
+			m.previous = append(m.previous, m.query.Cursor)
+			m.query.Cursor = m.page.NextCursor
+			m.selected = 0
+			return m, m.load()
+		}
+	case "b", "left":
+		if m.view == listView && len(m.previous) > 0 {
+			m.query.Cursor = m.previous[len(m.previous)-1]
+			m.previous = m.previous[:len(m.previous)-1]
+			m.selected = 0
+			return m, m.load()
+		}
+	case "r":
+		return m, m.load()
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	var b strings.Builder
+	if m.view == detailView && m.current() != nil {
+		m.viewDetail(&b)
+	} else {
+		m.viewList(&b)
+	}
+
+	b.WriteString("\n")
+	switch {
+	case m.searching:
+		b.WriteString(m.search.View() + "\n")
+	case m.editing:
+		b.WriteString(m.price.View() + "\n")
+	}
+	if m.err != nil {
+		b.WriteString(describe(m.err) + "\n")
+	} else if m.status != "" {
+		b.WriteString(m.status + "\n")
+	}
+
+	switch {
+	case m.searching:
+		b.WriteString("enter search  esc cancel")
+	case m.editing:
+		b.WriteString("enter save  esc cancel")
+	case m.view == detailView:
+		b.WriteString("e enable/disable  p price  esc back  q quit")
+	default:
+		b.WriteString("up/down move  enter open  / search  e enable/disable  p price  n/b page  r reload  q quit")
+	}
+	return b.String() + "\n"
+}
+
+func (m Model) viewList(b *strings.Builder) {
+	title := "Products"
+	if m.query.Name != "" {
+		title += fmt.Sprintf(" matching %q", m.query.Name)
+	}
+	fmt.Fprintf(b, "%s (page %d)\n\n", title, len(m.previous)+1)
+	fmt.Fprintf(b, "  %-30s %15s  %s\n", "NAME", "PRICE", "STATUS")
+	if len(m.page.Products) == 0 {
+		b.WriteString("  No products\n")
+	}
+	for i, product := range m.page.Products {
+		marker := " "
+		if i == m.selected {
+			marker = ">"
+		}
+		fmt.Fprintf(b, "%s %-30s %15s  %s\n", marker, truncate(product.GetName(), 30), product.GetPrice(), product.GetStatus())
+	}
+}
+
+func (m Model) viewDetail(b *strings.Builder) {
+	product := m.current()
+	fmt.Fprintf(b, "%s\n\n", product.GetName())
+	fmt.Fprintf(b, "  Id       %s\n", product.GetId())
+	fmt.Fprintf(b, "  Price    %s\n", product.GetPrice())
+	fmt.Fprintf(b, "  Status   %s\n", product.GetStatus())
+	fmt.Fprintf(b, "  Version  %d\n", product.GetVersion())
+}
+
+// describe lists every invalid field of a validation error on its own line.
+func describe(err error) string {
+	var validationErr *application.ValidationError
+	if errors.As(err, &validationErr) && len(validationErr.Fields) > 0 {
+		var lines []string
+		for _, field := range validationErr.Fields {
+			lines = append(lines, fmt.Sprintf("Error: %s: %s", field.Field, field.Message))
+		}
+		return strings.Join(lines, "\n")
+	}
+	return "Error: " + err.Error()
+}
+
+func truncate(value string, width int) string {
+	runes := []rune(value)
+	if len(runes) <= width {
+		return value
+	}
+	return string(runes[:width-3]) + "..."
+}
@@ -0,0 +1,175 @@
+package tui_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/memory"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/tui"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/stretchr/testify/assert"
+)
+
+// send delivers msg and then every message produced by the commands it
+// triggers, the way the program loop would.
+func send(model tea.Model, msg tea.Msg) tea.Model {
+	model, cmd := model.Update(msg)
+	for cmd != nil {
+		next := cmd()
+		if next == nil {
+			break
+		}
+		if _, ok := next.(tea.QuitMsg); ok {
+			break
+		}
+		model, cmd = model.Update(next)
+	}
+	return model
+}
+
+func press(model tea.Model, keys ...string) tea.Model {
+	for _, key := range keys {
+		switch key {
+		case "enter":
+			model = send(model, tea.KeyMsg{Type: tea.KeyEnter})
+		case "esc":
+			model = send(model, tea.KeyMsg{Type: tea.KeyEsc})
+		case "backspace":
+			model = send(model, tea.KeyMsg{Type: tea.KeyBackspace})
+		case "down":
+			model = send(model, tea.KeyMsg{Type: tea.KeyDown})
+		default:
+			for _, r := range key {
+				model = send(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+			}
+		}
+	}
+	return model
+}
+
+type failingSaves struct {
+	*memory.ProductMemory
+}
+
+func (p failingSaves) Save(ctx context.Context, product application.ProductInterface) (application.ProductInterface, error) {
+	return nil, errors.New("disk I/O error")
+}
+
+func start(t *testing.T, names ...string) (tea.Model, application.ProductServiceInterface) {
+	service := application.NewProductService(memory.NewProductMemory())
+	for i, name := range names {
+		_, err := service.Create(context.Background(), name, application.NewMoney(int64(i+1)*1000, application.DEFAULT_CURRENCY))
+		assert.Nil(t, err)
+	}
+
+	var model tea.Model = tui.NewModel(context.Background(), service)
+	model = send(model, model.Init()())
+	return model, service
+}
+
+func TestModel(t *testing.T) {
+	t.Run("Success - List products", func(t *testing.T) {
+		model, _ := start(t, "Keyboard", "Mouse")
+		view := model.View()
+		assert.Contains(t, view, "> Keyboard")
+		assert.Contains(t, view, "10.00 BRL")
+		assert.Contains(t, view, "  Mouse")
+	})
+
+	t.Run("Success - Search by name", func(t *testing.T) {
+		model, _ := start(t, "Keyboard", "Mouse")
+		model = press(model, "/", "mou", "enter")
+		view := model.View()
+		assert.Contains(t, view, `Products matching "mou"`)
+		assert.Contains(t, view, "> Mouse")
+		assert.NotContains(t, view, "Keyboard")
+	})
+
+	t.Run("Success - Open a product", func(t *testing.T) {
+		model, _ := start(t, "Keyboard", "Mouse")
+		model = press(model, "down", "enter")
+		view := model.View()
+		assert.Contains(t, view, "Mouse\n")
+		assert.Contains(t, view, "Version  1")
+
+		model = press(model, "esc")
+		assert.Contains(t, model.View(), "> Mouse")
+	})
+
+	t.Run("Success - Enable a product", func(t *testing.T) {
+		model, service := start(t, "Keyboard")
+		model = press(model, "e")
+		assert.Contains(t, model.View(), "Saved Keyboard")
+
+		page, err := service.List(context.Background(), application.ProductQuery{Status: application.ENABLED})
+		assert.Nil(t, err)
+		assert.Len(t, page.Products, 1)
+	})
+
+	t.Run("Error - Show domain errors inline", func(t *testing.T) {
+		model, _ := start(t, "Keyboard")
+		model = press(model, "e", "e")
+		assert.Contains(t, model.View(), "Error: The price must be zero to disable the product")
+
+		model = press(model, "j")
+		assert.NotContains(t, model.View(), "Error:")
+	})
+
+	t.Run("Success - Edit the price", func(t *testing.T) {
+		model, service := start(t, "Keyboard")
+		model = press(model, "enter", "p")
+		assert.Contains(t, model.View(), "New price (BRL): 10.00")
+
+		model = press(model, "backspace", "backspace", "backspace", "backspace", "backspace", "24.90", "enter")
+		view := model.View()
+		assert.Contains(t, view, "Price    24.90 BRL")
+		assert.Contains(t, view, "Saved Keyboard")
+
+		page, err := service.List(context.Background(), application.ProductQuery{})
+		assert.Nil(t, err)
+		assert.Equal(t, application.NewMoney(2490, application.DEFAULT_CURRENCY), page.Products[0].GetPrice())
+	})
+
+	t.Run("Error - Show validation errors while editing the price", func(t *testing.T) {
+		model, _ := start(t, "Keyboard")
+		model = press(model, "p", "9", "enter")
+		view := model.View()
+		assert.Contains(t, view, "Error: price: The price must have at most 2 decimal places for BRL")
+		assert.Contains(t, view, "New price (BRL): 10.009")
+
+		model = press(model, "esc")
+		assert.NotContains(t, model.View(), "Error:")
+	})
+
+	t.Run("Error - A failed save leaves the list unchanged", func(t *testing.T) {
+		persistence := memory.NewProductMemory()
+		service := application.NewProductService(failingSaves{persistence})
+		_, err := application.NewProductService(persistence).Create(context.Background(), "Keyboard", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+		assert.Nil(t, err)
+		var model tea.Model = tui.NewModel(context.Background(), service)
+		model = send(model, model.Init()())
+
+		model = press(model, "enter", "p", "backspace", "backspace", "backspace", "backspace", "backspace", "24.90", "enter")
+		view := model.View()
+		assert.Contains(t, view, "Error: disk I/O error")
+		assert.Contains(t, view, "Price    10.00 BRL")
+		assert.Contains(t, view, "Version  1")
+	})
+
+	t.Run("Success - Page through the products", func(t *testing.T) {
+		model, _ := start(t, "A", "B", "C")
+		model = send(model, tea.WindowSizeMsg{Width: 80, Height: 9})
+		assert.Contains(t, model.View(), "(page 1)")
+		assert.NotContains(t, model.View(), "  C ")
+
+		model = press(model, "n")
+		view := model.View()
+		assert.Contains(t, view, "(page 2)")
+		assert.Contains(t, view, "> C")
+
+		model = press(model, "b")
+		assert.Contains(t, model.View(), "> A")
+	})
+}
@@ -0,0 +1,10 @@
+package web
+
+// Routes lists the patterns Handler registers, for the drift test.
+func Routes(w *Webserver) []string {
+	var patterns []string
+	for _, route := range w.routes(OpenAPI()) {
+		patterns = append(patterns, route.pattern)
+	}
+	return patterns
+}
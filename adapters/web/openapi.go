@@ -0,0 +1,262 @@
+package web
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+)
+
+const decimalPattern = `^[+-]?(\d+\.?\d*|\.\d+)$`
+
+// OpenAPI describes the REST API. The statuses and price constraints come
+// from the application package, so the document changes with the domain.
+func OpenAPI() *openapi3.T {
+	statuses := []any{application.DISABLED, application.ENABLED, application.ARCHIVED}
+	currency := openapi3.NewStringSchema().WithPattern(`^[A-Za-z]{3}$`)
+	currency.Description = "ISO 4217 currency code, " + application.DEFAULT_CURRENCY + " by default"
+	// Without a type, the minimum applies to numbers and the pattern to strings.
+	price := openapi3.NewSchema().WithMin(0).WithPattern(`^\+?(\d+\.?\d*|\.\d+)$`)
+	price.Description = "Non-negative number or decimal string with at most as many decimal places as the currency allows"
+
+	fieldError := openapi3.NewObjectSchema().
+		WithProperty("field", openapi3.NewStringSchema()).
+		WithProperty("message", openapi3.NewStringSchema()).
+		WithRequired([]string{"field", "message"})
+	errorResponse := openapi3.NewObjectSchema().
+		WithProperty("message", openapi3.NewStringSchema()).
+		WithProperty("fields", openapi3.NewArraySchema().WithItems(fieldError)).
+		WithRequired([]string{"message"})
+	product := openapi3.NewObjectSchema().
+		WithProperty("id", openapi3.NewUUIDSchema()).
+		WithProperty("name", openapi3.NewStringSchema().WithMinLength(1)).
+		WithProperty("price", openapi3.NewFloat64Schema().WithMin(0)).
+		WithProperty("currency", openapi3.NewStringSchema().WithPattern(`^[A-Z]{3}$`)).
+		WithProperty("status", openapi3.NewStringSchema().WithEnum(statuses...)).
+		WithProperty("version", openapi3.NewIntegerSchema().WithMin(1)).
+		WithRequired([]string{"id", "name", "price", "currency", "status", "version"})
+	productRef := schemaRef("Product", product)
+	products := openapi3.NewArraySchema()
+	products.Items = productRef
+	productList := openapi3.NewObjectSchema().
+		WithProperty("products", products).
+		WithProperty("next_cursor", openapi3.NewStringSchema()).
+		WithRequired([]string{"products"})
+	productChange := openapi3.NewObjectSchema().
+		WithProperty("version", openapi3.NewIntegerSchema().WithMin(1)).
+		WithProperty("field", openapi3.NewStringSchema().WithEnum("name", "price", "status")).
+		WithProperty("old_value", openapi3.NewStringSchema().WithNullable()).
+		WithProperty("new_value", openapi3.NewStringSchema()).
+		WithProperty("actor", openapi3.NewStringSchema()).
+		WithProperty("changed_at", openapi3.NewDateTimeSchema()).
+		WithRequired([]string{"version", "field", "old_value", "new_value", "actor", "changed_at"})
+	changes := openapi3.NewArraySchema()
+	changes.Items = schemaRef("ProductChange", productChange)
+	createProduct := openapi3.NewObjectSchema().
+		WithProperty("name", openapi3.NewStringSchema().WithMinLength(1)).
+		WithProperty("price", price).
+		WithProperty("currency", currency).
+		WithRequired([]string{"name", "price"})
+
+	errorRef := schemaRef("ErrorResponse", errorResponse)
+	responses := func(status int, description string, schema *openapi3.SchemaRef, failures ...int) *openapi3.Responses {
+		options := []openapi3.NewResponsesOption{openapi3.WithStatus(status, jsonResponse(description, schema))}
+		for _, status := range failures {
+			options = append(options, openapi3.WithStatus(status, jsonResponse(http.StatusText(status), errorRef)))
+		}
+		return openapi3.NewResponses(options...)
+	}
+	operation := func(id, summary string, parameters openapi3.Parameters, responses *openapi3.Responses) *openapi3.Operation {
+		return &openapi3.Operation{OperationID: id, Summary: summary, Parameters: parameters, Responses: responses}
+	}
+	idParameter := openapi3.Parameters{{Value: openapi3.NewPathParameter("id").WithSchema(openapi3.NewStringSchema())}}
+
+	listParameters := openapi3.Parameters{
+		{Value: openapi3.NewQueryParameter("status").WithSchema(openapi3.NewStringSchema().WithEnum(statuses...))},
+		{Value: openapi3.NewQueryParameter("name").WithSchema(openapi3.NewStringSchema())},
+		{Value: openapi3.NewQueryParameter("sort").WithSchema(openapi3.NewStringSchema().WithEnum(application.SORT_BY_NAME, application.SORT_BY_PRICE))},
+		{Value: openapi3.NewQueryParameter("desc").WithSchema(openapi3.NewBoolSchema())},
+		{Value: openapi3.NewQueryParameter("min_price").WithSchema(openapi3.NewStringSchema().WithPattern(decimalPattern))},
+		{Value: openapi3.NewQueryParameter("max_price").WithSchema(openapi3.NewStringSchema().WithPattern(decimalPattern))},
+		{Value: openapi3.NewQueryParameter("currency").WithSchema(currency)},
+		{Value: openapi3.NewQueryParameter("limit").WithSchema(openapi3.NewIntegerSchema().WithMin(0).WithDefault(application.DEFAULT_PAGE_SIZE))},
+		{Value: openapi3.NewQueryParameter("cursor").WithSchema(openapi3.NewStringSchema())},
+	}
+	listParameters[7].Value.Description = fmt.Sprintf("Page size, at most %d", application.MAX_PAGE_SIZE)
+
+	create := operation("createProduct", "Create a disabled product", nil,
+		responses(http.StatusCreated, "The created product", productRef, http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity, http.StatusInternalServerError))
+	create.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithRequired(true).WithJSONSchemaRef(schemaRef("CreateProductRequest", createProduct))}
+
+	return &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info:    &openapi3.Info{Title: "Products", Version: "1.0.0"},
+		Paths: openapi3.NewPaths(
+			openapi3.WithPath("/products", &openapi3.PathItem{
+				Get: operation("listProducts", "List products", listParameters,
+					responses(http.StatusOK, "A page of products", schemaRef("ProductList", productList), http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusInternalServerError)),
+				Post: create,
+			}),
+			openapi3.WithPath("/products/{id}", &openapi3.PathItem{
+				Get: operation("getProduct", "Get a product", idParameter,
+					responses(http.StatusOK, "The product", productRef, http.StatusNotFound, http.StatusInternalServerError)),
+			}),
+			openapi3.WithPath("/products/{id}/enable", &openapi3.PathItem{
+				Post: operation("enableProduct", "Enable a product with a positive price", idParameter,
+					responses(http.StatusOK, "The enabled product", productRef, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError)),
+			}),
+			openapi3.WithPath("/products/{id}/disable", &openapi3.PathItem{
+				Post: operation("disableProduct", "Disable a product with a zero price", idParameter,
+					responses(http.StatusOK, "The disabled product", productRef, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError)),
+			}),
+			openapi3.WithPath("/products/{id}/history", &openapi3.PathItem{
+				Get: operation("productHistory", "List the changes made to a product", idParameter,
+					responses(http.StatusOK, "The changes, oldest first", changes.NewRef(), http.StatusNotFound, http.StatusInternalServerError)),
+			}),
+			openapi3.WithPath("/openapi.json", &openapi3.PathItem{
+				Get: operation("openAPI", "This document", nil,
+					responses(http.StatusOK, "The OpenAPI document", openapi3.NewObjectSchema().NewRef())),
+			}),
+		),
+		Components: &openapi3.Components{
+			Schemas: openapi3.Schemas{
+				"Product":              product.NewRef(),
+				"ProductList":          productList.NewRef(),
+				"ProductChange":        productChange.NewRef(),
+				"CreateProductRequest": createProduct.NewRef(),
+				"ErrorResponse":        errorResponse.NewRef(),
+			},
+		},
+	}
+}
+
+// schemaRef points to a component while keeping its value for validation.
+func schemaRef(name string, schema *openapi3.Schema) *openapi3.SchemaRef {
+	return openapi3.NewSchemaRef("#/components/schemas/"+name, schema)
+}
+
+func jsonResponse(description string, schema *openapi3.SchemaRef) *openapi3.ResponseRef {
+	return &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription(description).WithContent(openapi3.NewContentWithJSONSchemaRef(schema))}
+}
+
+// newRouter panics because the document is built in code, so an invalid one
+// is a programming error.
+func newRouter(document *openapi3.T) routers.Router {
+	router, err := gorillamux.NewRouter(document)
+	if err != nil {
+		panic(err)
+	}
+	return router
+}
+
+// MAX_BODY_SIZE bounds the request bodies read for validation, in bytes.
+const MAX_BODY_SIZE = 1 << 20
+
+// readBody reads the whole request body, answering 413 when it is over
+// MAX_BODY_SIZE.
+func readBody(rw http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(rw, r.Body, MAX_BODY_SIZE))
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		writeError(rw, err, http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+	if err != nil {
+		writeError(rw, err, http.StatusBadRequest)
+		return nil, false
+	}
+	return body, true
+}
+
+// limitBody applies MAX_BODY_SIZE to routes the document does not describe.
+func limitBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Body != nil && r.Body != http.NoBody {
+			body, ok := readBody(rw, r)
+			if !ok {
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// validateRequest rejects requests that do not match the document before
+// they reach a handler. Requests to paths it does not describe pass through.
+func validateRequest(router routers.Router, next http.Handler) http.Handler {
+	options := &openapi3filter.Options{
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(rw, r)
+			return
+		}
+
+		// Bodies are always decoded as JSON, whatever the Content-Type says.
+		input := r.Clone(r.Context())
+		if r.Body != nil && r.Body != http.NoBody {
+			body, ok := readBody(rw, r)
+			if !ok {
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			input.Body = io.NopCloser(bytes.NewReader(body))
+			input.Header.Set("Content-Type", "application/json")
+		}
+
+		err = openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+			Request:    input,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		})
+		if err != nil {
+			writeRequestError(rw, err)
+			return
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
+
+func writeRequestError(rw http.ResponseWriter, err error) {
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		writeError(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	var parseErr *openapi3filter.ParseError
+	if errors.As(requestErr, &parseErr) {
+		writeJSON(rw, http.StatusBadRequest, ErrorResponse{Message: parseErr.RootCause().Error()})
+		return
+	}
+
+	field := "body"
+	if requestErr.Parameter != nil {
+		field = requestErr.Parameter.Name
+	}
+	message := requestErr.Reason
+	var schemaErr *openapi3.SchemaError
+	if errors.As(requestErr, &schemaErr) {
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 && requestErr.Parameter == nil {
+			field = strings.Join(pointer, ".")
+		}
+		message = schemaErr.Reason
+	}
+	message = fmt.Sprintf("Invalid %s: %s", field, message)
+	writeJSON(rw, http.StatusUnprocessableEntity, ErrorResponse{
+		Message: message,
+		Fields:  []FieldError{{Field: field, Message: message}},
+	})
+}
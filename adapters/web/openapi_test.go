@@ -0,0 +1,138 @@
+package web_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/golang/mock/gomock"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/web"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// validateResponse fails the test when a handler answers with a status or a
+// body that the OpenAPI document does not describe.
+func validateResponse(t *testing.T, request *http.Request, response *httptest.ResponseRecorder) {
+	t.Helper()
+
+	router, err := gorillamux.NewRouter(web.OpenAPI())
+	assert.Nil(t, err)
+	route, pathParams, err := router.FindRoute(request)
+	if !assert.Nil(t, err, "%s %s is not documented", request.Method, request.URL.Path) {
+		return
+	}
+
+	err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    request,
+			PathParams: pathParams,
+			Route:      route,
+		},
+		Status: response.Code,
+		Header: response.Header(),
+		Body:   io.NopCloser(bytes.NewReader(response.Body.Bytes())),
+	})
+	assert.Nil(t, err)
+}
+
+func TestOpenAPI(t *testing.T) {
+	handler := web.NewWebserver(nil).Handler()
+
+	request := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+	validateResponse(t, request, response)
+
+	document, err := openapi3.NewLoader().LoadFromData(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.Nil(t, document.Validate(context.Background()))
+
+	status := document.Components.Schemas["Product"].Value.Properties["status"].Value
+	assert.ElementsMatch(t, []any{application.DISABLED, application.ENABLED, application.ARCHIVED}, status.Enum)
+	price := document.Components.Schemas["CreateProductRequest"].Value.Properties["price"].Value
+	assert.Equal(t, float64(0), *price.Min)
+}
+
+func TestOpenAPIDrift(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	serviceMock := mock.NewMockProductServiceInterface(ctrl)
+	serviceMock.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, application.ErrProductNotFound).AnyTimes()
+	serviceMock.EXPECT().History(gomock.Any(), gomock.Any()).Return(nil, application.ErrProductNotFound).AnyTimes()
+	serviceMock.EXPECT().List(gomock.Any(), gomock.Any()).Return(application.ProductPage{}, nil).AnyTimes()
+	created := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+	created.SetVersion(1)
+	serviceMock.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(created, nil).AnyTimes()
+
+	handler := web.NewWebserver(serviceMock).Handler()
+
+	for path, item := range web.OpenAPI().Paths.Map() {
+		for method := range item.Operations() {
+			t.Run("Success - "+method+" "+path+" is served", func(t *testing.T) {
+				var body io.Reader
+				if method == http.MethodPost && path == "/products" {
+					body = strings.NewReader(`{"name":"Product 1","price":10}`)
+				}
+				request := httptest.NewRequest(method, strings.ReplaceAll(path, "{id}", "1"), body)
+				response := httptest.NewRecorder()
+				handler.ServeHTTP(response, request)
+
+				// The mux answers unknown routes in plain text.
+				assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+				validateResponse(t, request, response)
+			})
+		}
+	}
+
+	for _, pattern := range web.Routes(web.NewWebserver(serviceMock)) {
+		t.Run("Success - "+pattern+" is documented", func(t *testing.T) {
+			method, path, _ := strings.Cut(pattern, " ")
+			item := web.OpenAPI().Paths.Value(path)
+			if assert.NotNil(t, item) {
+				assert.NotNil(t, item.GetOperation(method))
+			}
+		})
+	}
+
+	t.Run("Error - Requests that break the contract do not reach the service", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"name":"Product 1","price":-1}`))
+		response := httptest.NewRecorder()
+		handler.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+		assert.Contains(t, response.Body.String(), `"field":"price"`)
+		validateResponse(t, request, response)
+	})
+
+	t.Run("Error - Bodies over the size limit are rejected", func(t *testing.T) {
+		name := strings.Repeat("a", web.MAX_BODY_SIZE)
+		request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"name":"`+name+`","price":10}`))
+		response := httptest.NewRecorder()
+		handler.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, response.Code)
+		validateResponse(t, request, response)
+	})
+
+	for path, item := range web.OpenAPI().Paths.Map() {
+		for method, operation := range item.Operations() {
+			if operation.RequestBody == nil {
+				continue
+			}
+			t.Run("Success - "+method+" "+path+" documents the size limit", func(t *testing.T) {
+				assert.NotNil(t, operation.Responses.Status(http.StatusRequestEntityTooLarge))
+			})
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+)
+
+type Product struct {
+	Id       string      `json:"id"`
+	Name     string      `json:"name"`
+	Price    json.Number `json:"price"`
+	Currency string      `json:"currency"`
+	Status   string      `json:"status"`
+	Version  int         `json:"version"`
+}
+
+type ProductList struct {
+	Products   []Product `json:"products"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+type ProductChange struct {
+	Version   int       `json:"version"`
+	Field     string    `json:"field"`
+	OldValue  *string   `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	Actor     string    `json:"actor"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type CreateProductRequest struct {
+	Name     string      `json:"name"`
+	Price    json.Number `json:"price"`
+	Currency string      `json:"currency"`
+}
+
+type ErrorResponse struct {
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func newProduct(product application.ProductInterface) Product {
+	return Product{
+		Id:       product.GetId(),
+		Name:     product.GetName(),
+		Price:    json.Number(product.GetPrice().Decimal()),
+		Currency: product.GetPrice().Currency,
+		Status:   product.GetStatus(),
+		Version:  product.GetVersion(),
+	}
+}
+
+func (w *Webserver) listProducts(rw http.ResponseWriter, r *http.Request) {
+	query, err := newProductQuery(r)
+	if err != nil {
+		writeError(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	page, err := w.Service.List(r.Context(), query)
+	if err != nil {
+		writeError(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	result := ProductList{Products: []Product{}, NextCursor: page.NextCursor}
+	for _, product := range page.Products {
+		result.Products = append(result.Products, newProduct(product))
+	}
+	writeJSON(rw, http.StatusOK, result)
+}
+
+func newProductQuery(r *http.Request) (application.ProductQuery, error) {
+	values := r.URL.Query()
+	query := application.ProductQuery{
+		Status: values.Get("status"),
+		Name:   values.Get("name"),
+		SortBy: values.Get("sort"),
+		Cursor: values.Get("cursor"),
+	}
+
+	var err error
+	currency := values.Get("currency")
+	if currency == "" {
+		currency = application.DEFAULT_CURRENCY
+	}
+	if value := values.Get("min_price"); value != "" {
+		if query.MinPrice, err = parsePrice(value, currency); err != nil {
+			return query, err
+		}
+	}
+	if value := values.Get("max_price"); value != "" {
+		if query.MaxPrice, err = parsePrice(value, currency); err != nil {
+			return query, err
+		}
+	}
+	if value := values.Get("desc"); value != "" {
+		if query.Desc, err = strconv.ParseBool(value); err != nil {
+			return query, err
+		}
+	}
+	if value := values.Get("limit"); value != "" {
+		if query.Limit, err = strconv.Atoi(value); err != nil {
+			return query, err
+		}
+	}
+	return query, nil
+}
+
+func parsePrice(value, currency string) (*application.Money, error) {
+	price, err := application.ParseMoney(value, currency)
+	if err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+func (w *Webserver) getProduct(rw http.ResponseWriter, r *http.Request) {
+	product, err := w.Service.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(rw, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(rw, http.StatusOK, newProduct(product))
+}
+
+func (w *Webserver) createProduct(rw http.ResponseWriter, r *http.Request) {
+	var input CreateProductRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	if input.Currency == "" {
+		input.Currency = application.DEFAULT_CURRENCY
+	}
+	price, err := application.ParseMoney(input.Price.String(), input.Currency)
+	if err != nil {
+		writeError(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	product, err := w.Service.Create(r.Context(), input.Name, price)
+	if err != nil {
+		writeError(rw, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(rw, http.StatusCreated, newProduct(product))
+}
+
+func (w *Webserver) enableProduct(rw http.ResponseWriter, r *http.Request) {
+	product, err := w.Service.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	product, err = w.Service.Enable(r.Context(), product)
+	if err != nil {
+		writeError(rw, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(rw, http.StatusOK, newProduct(product))
+}
+
+func (w *Webserver) disableProduct(rw http.ResponseWriter, r *http.Request) {
+	product, err := w.Service.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	product, err = w.Service.Disable(r.Context(), product)
+	if err != nil {
+		writeError(rw, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(rw, http.StatusOK, newProduct(product))
+}
+
+func (w *Webserver) productHistory(rw http.ResponseWriter, r *http.Request) {
+	changes, err := w.Service.History(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	result := []ProductChange{}
+	for _, change := range changes {
+		result = append(result, ProductChange{
+			Version:   change.Version,
+			Field:     change.Field,
+			OldValue:  change.OldValue,
+			NewValue:  change.NewValue,
+			Actor:     change.Actor,
+			ChangedAt: change.ChangedAt,
+		})
+	}
+	writeJSON(rw, http.StatusOK, result)
+}
+
+func writeJSON(rw http.ResponseWriter, status int, body any) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	json.NewEncoder(rw).Encode(body)
+}
+
+func writeError(rw http.ResponseWriter, err error, fallback int) {
+	status := statusCode(err, fallback)
+	response := ErrorResponse{Message: err.Error()}
+	if status == http.StatusInternalServerError {
+		response.Message = http.StatusText(status)
+	}
+
+	var validationErr *application.ValidationError
+	if errors.As(err, &validationErr) {
+		for _, field := range validationErr.Fields {
+			response.Fields = append(response.Fields, FieldError{Field: field.Field, Message: field.Message})
+		}
+	}
+	writeJSON(rw, status, response)
+}
+
+func statusCode(err error, fallback int) int {
+	var validationErr *application.ValidationError
+	switch {
+	case errors.Is(err, application.ErrProductNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, application.ErrInvalidTransition), errors.Is(err, application.ErrConcurrentModification):
+		return http.StatusConflict
+	case errors.As(err, &validationErr), errors.Is(err, application.ErrInvalidPrice):
+		return http.StatusUnprocessableEntity
+	default:
+		return fallback
+	}
+}
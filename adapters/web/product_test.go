@@ -0,0 +1,269 @@
+package web_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/web"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebserver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productName := "Product 1"
+	productPrice := application.NewMoney(1999, application.DEFAULT_CURRENCY)
+	productStatus := "enabled"
+	productId := "681051e4-2936-4b4c-87a4-efaf7b8c02ba"
+
+	productMock := mock.NewMockProductInterface(ctrl)
+	productMock.EXPECT().GetId().Return(productId).AnyTimes()
+	productMock.EXPECT().GetName().Return(productName).AnyTimes()
+	productMock.EXPECT().GetPrice().Return(productPrice).AnyTimes()
+	productMock.EXPECT().GetStatus().Return(productStatus).AnyTimes()
+	productMock.EXPECT().GetVersion().Return(3).AnyTimes()
+
+	serviceMock := mock.NewMockProductServiceInterface(ctrl)
+	serviceMock.EXPECT().Create(gomock.Any(), productName, productPrice).Return(productMock, nil).AnyTimes()
+	serviceMock.EXPECT().Create(gomock.Any(), "", gomock.Any()).Return(nil, application.NewValidationError("name", "Name: non zero value required", nil)).AnyTimes()
+	serviceMock.EXPECT().Get(gomock.Any(), productId).Return(productMock, nil).AnyTimes()
+	serviceMock.EXPECT().Get(gomock.Any(), "missing").Return(nil, application.ErrProductNotFound).AnyTimes()
+	conflicted := application.NewProduct("Conflicted", productPrice)
+	serviceMock.EXPECT().Get(gomock.Any(), "conflict").Return(conflicted, nil).AnyTimes()
+	serviceMock.EXPECT().Enable(gomock.Any(), conflicted).Return(nil, application.ErrConcurrentModification).AnyTimes()
+	serviceMock.EXPECT().Get(gomock.Any(), "broken").Return(nil, errors.New("database is locked")).AnyTimes()
+	serviceMock.EXPECT().Enable(gomock.Any(), productMock).Return(productMock, nil).AnyTimes()
+	minPrice := application.NewMoney(1050, application.DEFAULT_CURRENCY)
+	serviceMock.EXPECT().List(gomock.Any(), application.ProductQuery{Status: "enabled", SortBy: "price", Desc: true, Limit: 1, MinPrice: &minPrice}).Return(application.ProductPage{
+		Products:   []application.ProductInterface{productMock},
+		NextCursor: "next",
+	}, nil).AnyTimes()
+	serviceMock.EXPECT().List(gomock.Any(), application.ProductQuery{Status: "invalid"}).Return(application.ProductPage{}, application.NewValidationError("status", "The status filter must be enabled, disabled or archived", nil)).AnyTimes()
+	serviceMock.EXPECT().Disable(gomock.Any(), productMock).Return(nil, &application.TransitionError{From: application.ENABLED, To: application.DISABLED, Reason: "The price must be zero to disable the product"}).AnyTimes()
+
+	changedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	oldPrice := "10.00 BRL"
+	serviceMock.EXPECT().History(gomock.Any(), productId).Return([]application.ProductChange{
+		{ProductId: productId, Version: 2, Field: "price", OldValue: &oldPrice, NewValue: "19.99 BRL", Actor: "alice", ChangedAt: changedAt},
+	}, nil).AnyTimes()
+	serviceMock.EXPECT().History(gomock.Any(), "missing").Return(nil, application.ErrProductNotFound).AnyTimes()
+
+	productJSON := `{"id":"681051e4-2936-4b4c-87a4-efaf7b8c02ba","name":"Product 1","price":19.99,"currency":"BRL","status":"enabled","version":3}`
+
+	tests := []struct {
+		testName string
+		method   string
+		path     string
+		body     string
+		status   int
+		expected string
+	}{
+		{
+			testName: "Success - Get",
+			method:   http.MethodGet,
+			path:     "/products/" + productId,
+			status:   http.StatusOK,
+			expected: productJSON,
+		},
+		{
+			testName: "Error - Get a product that does not exist",
+			method:   http.MethodGet,
+			path:     "/products/missing",
+			status:   http.StatusNotFound,
+			expected: `{"message":"Product not found"}`,
+		},
+		{
+			testName: "Error - Get hides storage errors",
+			method:   http.MethodGet,
+			path:     "/products/broken",
+			status:   http.StatusInternalServerError,
+			expected: `{"message":"Internal Server Error"}`,
+		},
+		{
+			testName: "Success - List",
+			method:   http.MethodGet,
+			path:     "/products?status=enabled&sort=price&desc=true&limit=1&min_price=10.50",
+			status:   http.StatusOK,
+			expected: `{"products":[` + productJSON + `],"next_cursor":"next"}`,
+		},
+		{
+			testName: "Error - List with an invalid filter",
+			method:   http.MethodGet,
+			path:     "/products?status=invalid",
+			status:   http.StatusUnprocessableEntity,
+			expected: `{"message":"Invalid status: value is not one of the allowed values [\"disabled\",\"enabled\",\"archived\"]","fields":[{"field":"status","message":"Invalid status: value is not one of the allowed values [\"disabled\",\"enabled\",\"archived\"]"}]}`,
+		},
+		{
+			testName: "Error - List with an invalid price",
+			method:   http.MethodGet,
+			path:     "/products?min_price=abc",
+			status:   http.StatusUnprocessableEntity,
+			expected: `{"message":"Invalid min_price: string doesn't match the regular expression \"^[+-]?(\\d+\\.?\\d*|\\.\\d+)$\"","fields":[{"field":"min_price","message":"Invalid min_price: string doesn't match the regular expression \"^[+-]?(\\d+\\.?\\d*|\\.\\d+)$\""}]}`,
+		},
+		{
+			testName: "Success - Create",
+			method:   http.MethodPost,
+			path:     "/products",
+			body:     `{"name":"Product 1","price":19.99}`,
+			status:   http.StatusCreated,
+			expected: productJSON,
+		},
+		{
+			testName: "Success - Create with the price as a string",
+			method:   http.MethodPost,
+			path:     "/products",
+			body:     `{"name":"Product 1","price":"19.99","currency":"BRL"}`,
+			status:   http.StatusCreated,
+			expected: productJSON,
+		},
+		{
+			testName: "Error - Create with too many decimal places",
+			method:   http.MethodPost,
+			path:     "/products",
+			body:     `{"name":"Product 1","price":19.999}`,
+			status:   http.StatusUnprocessableEntity,
+			expected: `{"message":"The price must have at most 2 decimal places for BRL","fields":[{"field":"price","message":"The price must have at most 2 decimal places for BRL"}]}`,
+		},
+		{
+			testName: "Error - Create with an invalid body",
+			method:   http.MethodPost,
+			path:     "/products",
+			body:     `{"name":`,
+			status:   http.StatusBadRequest,
+			expected: `{"message":"unexpected EOF"}`,
+		},
+		{
+			testName: "Error - Create an invalid product",
+			method:   http.MethodPost,
+			path:     "/products",
+			body:     `{"name":"","price":10}`,
+			status:   http.StatusUnprocessableEntity,
+			expected: `{"message":"Invalid name: minimum string length is 1","fields":[{"field":"name","message":"Invalid name: minimum string length is 1"}]}`,
+		},
+		{
+			testName: "Success - Enable",
+			method:   http.MethodPost,
+			path:     "/products/" + productId + "/enable",
+			status:   http.StatusOK,
+			expected: productJSON,
+		},
+		{
+			testName: "Error - Disable",
+			method:   http.MethodPost,
+			path:     "/products/" + productId + "/disable",
+			status:   http.StatusConflict,
+			expected: `{"message":"The price must be zero to disable the product"}`,
+		},
+		{
+			testName: "Error - Enable a product modified concurrently",
+			method:   http.MethodPost,
+			path:     "/products/conflict/enable",
+			status:   http.StatusConflict,
+			expected: `{"message":"The product was modified by another operation"}`,
+		},
+		{
+			testName: "Error - Enable a product that does not exist",
+			method:   http.MethodPost,
+			path:     "/products/missing/enable",
+			status:   http.StatusNotFound,
+			expected: `{"message":"Product not found"}`,
+		},
+		{
+			testName: "Success - Product history",
+			method:   http.MethodGet,
+			path:     "/products/" + productId + "/history",
+			status:   http.StatusOK,
+			expected: `[{"version":2,"field":"price","old_value":"10.00 BRL","new_value":"19.99 BRL","actor":"alice","changed_at":"2026-01-02T03:04:05Z"}]`,
+		},
+		{
+			testName: "Error - History of a product that does not exist",
+			method:   http.MethodGet,
+			path:     "/products/missing/history",
+			status:   http.StatusNotFound,
+			expected: `{"message":"Product not found"}`,
+		},
+	}
+
+	handler := web.NewWebserver(serviceMock).Handler()
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			request := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			response := httptest.NewRecorder()
+
+			handler.ServeHTTP(response, request)
+
+			assert.Equal(t, tt.status, response.Code)
+			assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+			assert.JSONEq(t, tt.expected, response.Body.String())
+			validateResponse(t, request, response)
+		})
+	}
+}
+
+func TestWebserverActor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var actor string
+	serviceMock := mock.NewMockProductServiceInterface(ctrl)
+	serviceMock.EXPECT().History(gomock.Any(), "1").DoAndReturn(func(ctx context.Context, id string) ([]application.ProductChange, error) {
+		actor = application.ActorFromContext(ctx)
+		return nil, nil
+	}).Times(3)
+
+	server := web.NewWebserver(serviceMock)
+	// httptest requests come from 192.0.2.1.
+	server.TrustedProxies = []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}
+	handler := server.Handler()
+
+	request := httptest.NewRequest(http.MethodGet, "/products/1/history", nil)
+	request.Header.Set("X-Actor", "alice")
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	assert.Equal(t, "alice", actor)
+	assert.JSONEq(t, `[]`, response.Body.String())
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/products/1/history", nil))
+	assert.Equal(t, application.UNKNOWN_ACTOR, actor)
+
+	request = httptest.NewRequest(http.MethodGet, "/products/1/history", nil)
+	request.RemoteAddr = "203.0.113.7:41000"
+	request.Header.Set("X-Actor", "alice")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	assert.Equal(t, application.UNKNOWN_ACTOR, actor)
+}
+
+func TestWebserverGraphQL(t *testing.T) {
+	var actor string
+	server := web.NewWebserver(nil)
+	server.TrustedProxies = []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}
+	handler := server.Handler()
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodPost, "/graphql", nil))
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	server.GraphQL = http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		actor = application.ActorFromContext(r.Context())
+	})
+	request := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	request.Header.Set("X-Actor", "alice")
+	response = httptest.NewRecorder()
+	server.Handler().ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "alice", actor)
+
+	response = httptest.NewRecorder()
+	server.Handler().ServeHTTP(response, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(strings.Repeat(" ", web.MAX_BODY_SIZE+1))))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, response.Code)
+}
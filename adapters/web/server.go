@@ -0,0 +1,113 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/netip"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+)
+
+type Webserver struct {
+	Service application.ProductServiceInterface
+	// GraphQL, when set, is served on POST /graphql.
+	GraphQL http.Handler
+	// TrustedProxies are the addresses allowed to name the actor of a
+	// change in the X-Actor header, after authenticating the user.
+	TrustedProxies []netip.Prefix
+}
+
+func NewWebserver(service application.ProductServiceInterface) *Webserver {
+	return &Webserver{Service: service}
+}
+
+func (w *Webserver) Handler() http.Handler {
+	document := OpenAPI()
+	mux := http.NewServeMux()
+	for _, route := range w.routes(document) {
+		mux.Handle(route.pattern, route.handler)
+	}
+	return w.withActor(validateRequest(newRouter(document), mux))
+}
+
+type route struct {
+	pattern string
+	handler http.Handler
+}
+
+// Every route but GraphQL must be described by the OpenAPI document.
+func (w *Webserver) routes(document *openapi3.T) []route {
+	routes := []route{
+		{"GET /openapi.json", http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			writeJSON(rw, http.StatusOK, document)
+		})},
+		{"GET /products", http.HandlerFunc(w.listProducts)},
+		{"GET /products/{id}", http.HandlerFunc(w.getProduct)},
+		{"POST /products", http.HandlerFunc(w.createProduct)},
+		{"POST /products/{id}/enable", http.HandlerFunc(w.enableProduct)},
+		{"POST /products/{id}/disable", http.HandlerFunc(w.disableProduct)},
+		{"GET /products/{id}/history", http.HandlerFunc(w.productHistory)},
+	}
+	if w.GraphQL != nil {
+		routes = append(routes, route{"POST /graphql", limitBody(w.GraphQL)})
+	}
+	return routes
+}
+
+// Changes made through the API are attributed to the X-Actor header. The
+// header is not authenticated, so it is ignored unless a trusted proxy sent
+// the request.
+func (w *Webserver) withActor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if actor := r.Header.Get("X-Actor"); actor != "" && w.trusted(r.RemoteAddr) {
+			r = r.WithContext(application.WithActor(r.Context(), actor))
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
+
+func (w *Webserver) trusted(remoteAddr string) bool {
+	addr, err := netip.ParseAddrPort(remoteAddr)
+	if err != nil {
+		return false
+	}
+	for _, prefix := range w.TrustedProxies {
+		if prefix.Contains(addr.Addr().Unmap()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Webserver) Serve(ctx context.Context, addr string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           w.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+		if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package application
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+)
+
+var (
+	ErrProductNotFound        = errors.New("Product not found")
+	ErrInvalidPrice           = errors.New("Invalid price")
+	ErrInvalidTransition      = errors.New("Invalid status transition")
+	ErrConcurrentModification = errors.New("The product was modified by another operation")
+)
+
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+type ValidationError struct {
+	Fields []FieldError
+	Err    error
+}
+
+func NewValidationError(field, message string, err error) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: message}}, Err: err}
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+type TransitionError struct {
+	From   string
+	To     string
+	Reason string
+}
+
+func (e *TransitionError) Error() string {
+	return e.Reason
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+func newStructValidationError(err error) error {
+	var validationErrors govalidator.Errors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	result := &ValidationError{}
+	for _, item := range validationErrors.Errors() {
+		var fieldError govalidator.Error
+		if errors.As(item, &fieldError) {
+			result.Fields = append(result.Fields, FieldError{Field: strings.ToLower(fieldError.Name), Message: fieldError.Error()})
+			continue
+		}
+		result.Fields = append(result.Fields, FieldError{Message: item.Error()})
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+package application_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationError(t *testing.T) {
+	err := &application.ValidationError{
+		Fields: []application.FieldError{
+			{Field: "name", Message: "Name: non zero value required"},
+			{Field: "status", Message: "Status: invalid does not validate as in(disabled|enabled)"},
+		},
+		Err: application.ErrInvalidPrice,
+	}
+
+	assert.Equal(t, "Name: non zero value required; Status: invalid does not validate as in(disabled|enabled)", err.Error())
+	assert.ErrorIs(t, err, application.ErrInvalidPrice)
+}
+
+func TestTransitionError(t *testing.T) {
+	err := &application.TransitionError{From: application.DISABLED, To: application.ENABLED, Reason: "The price must be greater than zero to enable the product"}
+
+	assert.Equal(t, "The price must be greater than zero to enable the product", err.Error())
+	assert.ErrorIs(t, err, application.ErrInvalidTransition)
+}
+
+func TestProductErrors(t *testing.T) {
+	t.Run("Invalid fields are reported as a validation error", func(t *testing.T) {
+		product := &application.Product{Id: "invalid", Status: "invalid", Price: application.NewMoney(0, application.DEFAULT_CURRENCY)}
+
+		_, err := product.IsValid()
+
+		var validationErr *application.ValidationError
+		assert.True(t, errors.As(err, &validationErr))
+		fields := []string{}
+		for _, field := range validationErr.Fields {
+			fields = append(fields, field.Field)
+		}
+		assert.ElementsMatch(t, []string{"id", "name", "status"}, fields)
+	})
+
+	t.Run("Negative price is an invalid price", func(t *testing.T) {
+		product := application.NewProduct("Product 1", application.NewMoney(-1, application.DEFAULT_CURRENCY))
+
+		_, err := product.IsValid()
+		assert.ErrorIs(t, err, application.ErrInvalidPrice)
+		assert.ErrorIs(t, product.ChangePrice(application.NewMoney(-1, application.DEFAULT_CURRENCY)), application.ErrInvalidPrice)
+	})
+
+	t.Run("Status changes that break the price rules are invalid transitions", func(t *testing.T) {
+		product := &application.Product{Id: uuid.NewString(), Name: "Product 1", Status: application.DISABLED}
+
+		var transitionErr *application.TransitionError
+		assert.True(t, errors.As(product.Enable(), &transitionErr))
+		assert.Equal(t, application.DISABLED, transitionErr.From)
+		assert.Equal(t, application.ENABLED, transitionErr.To)
+
+		product.Price = application.NewMoney(1000, application.DEFAULT_CURRENCY)
+		assert.ErrorIs(t, product.Disable(), application.ErrInvalidTransition)
+	})
+}
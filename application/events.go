@@ -0,0 +1,172 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	PRODUCT_CREATED       = "product.created"
+	PRODUCT_ENABLED       = "product.enabled"
+	PRODUCT_DISABLED      = "product.disabled"
+	PRODUCT_PRICE_CHANGED = "product.price_changed"
+	PRODUCT_RENAMED       = "product.renamed"
+	PRODUCT_ARCHIVED      = "product.archived"
+	PRODUCT_RESTORED      = "product.restored"
+	PRODUCT_PURGED        = "product.purged"
+)
+
+type DomainEvent interface {
+	EventName() string
+	GetProductId() string
+	GetOccurredAt() time.Time
+}
+
+type EventHandler func(ctx context.Context, event DomainEvent) error
+
+type EventPublisherInterface interface {
+	Publish(ctx context.Context, events ...DomainEvent) error
+}
+
+// Handlers subscribed without event names receive every event.
+type EventBusInterface interface {
+	EventPublisherInterface
+	Subscribe(handler EventHandler, eventNames ...string)
+}
+
+type ProductEvent struct {
+	ProductId  string
+	OccurredAt time.Time
+}
+
+func newProductEvent(productId string) ProductEvent {
+	return ProductEvent{ProductId: productId, OccurredAt: time.Now().UTC()}
+}
+
+func (e ProductEvent) GetProductId() string {
+	return e.ProductId
+}
+
+func (e ProductEvent) GetOccurredAt() time.Time {
+	return e.OccurredAt
+}
+
+type ProductCreated struct {
+	ProductEvent
+	Name  string
+	Price Money
+}
+
+func (ProductCreated) EventName() string {
+	return PRODUCT_CREATED
+}
+
+type ProductEnabled struct {
+	ProductEvent
+}
+
+func (ProductEnabled) EventName() string {
+	return PRODUCT_ENABLED
+}
+
+type ProductDisabled struct {
+	ProductEvent
+}
+
+func (ProductDisabled) EventName() string {
+	return PRODUCT_DISABLED
+}
+
+type ProductPriceChanged struct {
+	ProductEvent
+	OldPrice Money
+	NewPrice Money
+}
+
+func (ProductPriceChanged) EventName() string {
+	return PRODUCT_PRICE_CHANGED
+}
+
+type ProductRenamed struct {
+	ProductEvent
+	OldName string
+	NewName string
+}
+
+func (ProductRenamed) EventName() string {
+	return PRODUCT_RENAMED
+}
+
+type ProductArchived struct {
+	ProductEvent
+}
+
+func (ProductArchived) EventName() string {
+	return PRODUCT_ARCHIVED
+}
+
+type ProductRestored struct {
+	ProductEvent
+}
+
+func (ProductRestored) EventName() string {
+	return PRODUCT_RESTORED
+}
+
+// ProductPurged only carries the id, as nothing else of a purged product is
+// kept.
+type ProductPurged struct {
+	ProductEvent
+}
+
+func NewProductPurged(productId string) ProductPurged {
+	return ProductPurged{ProductEvent: newProductEvent(productId)}
+}
+
+func (ProductPurged) EventName() string {
+	return PRODUCT_PURGED
+}
+
+// Events are stored as the JSON of their struct; UnmarshalEvent restores the
+// concrete type from the name it was stored under.
+func UnmarshalEvent(name string, data []byte) (DomainEvent, error) {
+	switch name {
+	case PRODUCT_CREATED:
+		return unmarshalEvent[ProductCreated](data)
+	case PRODUCT_ENABLED:
+		return unmarshalEvent[ProductEnabled](data)
+	case PRODUCT_DISABLED:
+		return unmarshalEvent[ProductDisabled](data)
+	case PRODUCT_PRICE_CHANGED:
+		return unmarshalEvent[ProductPriceChanged](data)
+	case PRODUCT_RENAMED:
+		return unmarshalEvent[ProductRenamed](data)
+	case PRODUCT_ARCHIVED:
+		return unmarshalEvent[ProductArchived](data)
+	case PRODUCT_RESTORED:
+		return unmarshalEvent[ProductRestored](data)
+	case PRODUCT_PURGED:
+		return unmarshalEvent[ProductPurged](data)
+	default:
+		return nil, fmt.Errorf("unknown event %q", name)
+	}
+}
+
+func unmarshalEvent[T DomainEvent](data []byte) (DomainEvent, error) {
+	var event T
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+type eventBuffer struct {
+	events []DomainEvent
+}
+
+func (b *eventBuffer) Publish(ctx context.Context, events ...DomainEvent) error {
+	b.events = append(b.events, events...)
+	return nil
+}
@@ -0,0 +1,35 @@
+package application_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalEvent(t *testing.T) {
+	product := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+	assert.Nil(t, product.ChangePrice(application.NewMoney(1500, application.DEFAULT_CURRENCY)))
+	assert.Nil(t, product.Enable())
+	assert.Nil(t, product.ChangeName("Product 2"))
+	assert.Nil(t, product.Archive())
+	assert.Nil(t, product.Restore())
+
+	for _, event := range product.Events() {
+		payload, err := json.Marshal(event)
+		assert.Nil(t, err)
+
+		result, err := application.UnmarshalEvent(event.EventName(), payload)
+		assert.Nil(t, err)
+		assert.Equal(t, event.EventName(), result.EventName())
+		assert.Equal(t, event.GetProductId(), result.GetProductId())
+		assert.True(t, event.GetOccurredAt().Equal(result.GetOccurredAt()))
+	}
+
+	_, err := application.UnmarshalEvent("product.sold", []byte("{}"))
+	assert.EqualError(t, err, `unknown event "product.sold"`)
+
+	_, err = application.UnmarshalEvent(application.PRODUCT_ENABLED, []byte("{"))
+	assert.NotNil(t, err)
+}
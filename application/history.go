@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+	"time"
+)
+
+const UNKNOWN_ACTOR = "unknown"
+
+type ProductChange struct {
+	ProductId string
+	Version   int
+	Field     string
+	OldValue  *string
+	NewValue  string
+	Actor     string
+	ChangedAt time.Time
+}
+
+// DiffProduct lists the fields of product that differ from previous as the
+// history entries of one save. A product seen for the first time, with a nil
+// previous, lists every field with no old value.
+func DiffProduct(previous, product ProductInterface, version int, actor string, changedAt time.Time) []ProductChange {
+	fields := []string{"name", "price", "status"}
+	values := func(product ProductInterface) []string {
+		return []string{product.GetName(), product.GetPrice().String(), product.GetStatus()}
+	}
+
+	var changes []ProductChange
+	current := values(product)
+	for i, field := range fields {
+		change := ProductChange{ProductId: product.GetId(), Version: version, Field: field, NewValue: current[i], Actor: actor, ChangedAt: changedAt}
+		if previous != nil {
+			old := values(previous)[i]
+			if old == current[i] {
+				continue
+			}
+			change.OldValue = &old
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+type actorKey struct{}
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return UNKNOWN_ACTOR
+}
+
+func (s *ProductService) History(ctx context.Context, id string) ([]ProductChange, error) {
+	changes, err := s.ProductPersistence.History(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -5,7 +5,10 @@
 package mock
 
 import (
+	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	application "github.com/sousapedro11/fc-arquitetura-hexagonal/application"
@@ -34,8 +37,36 @@ func (m *MockProductInterface) EXPECT() *MockProductInterfaceMockRecorder {
 	return m.recorder
 }
 
+// Archive mocks base method.
+func (m *MockProductInterface) Archive() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Archive")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockProductInterfaceMockRecorder) Archive() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockProductInterface)(nil).Archive))
+}
+
+// ChangeName mocks base method.
+func (m *MockProductInterface) ChangeName(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeName", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeName indicates an expected call of ChangeName.
+func (mr *MockProductInterfaceMockRecorder) ChangeName(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeName", reflect.TypeOf((*MockProductInterface)(nil).ChangeName), name)
+}
+
 // ChangePrice mocks base method.
-func (m *MockProductInterface) ChangePrice(price float64) error {
+func (m *MockProductInterface) ChangePrice(price application.Money) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ChangePrice", price)
 	ret0, _ := ret[0].(error)
@@ -48,6 +79,18 @@ func (mr *MockProductInterfaceMockRecorder) ChangePrice(price interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePrice", reflect.TypeOf((*MockProductInterface)(nil).ChangePrice), price)
 }
 
+// ClearEvents mocks base method.
+func (m *MockProductInterface) ClearEvents() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ClearEvents")
+}
+
+// ClearEvents indicates an expected call of ClearEvents.
+func (mr *MockProductInterfaceMockRecorder) ClearEvents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearEvents", reflect.TypeOf((*MockProductInterface)(nil).ClearEvents))
+}
+
 // Disable mocks base method.
 func (m *MockProductInterface) Disable() error {
 	m.ctrl.T.Helper()
@@ -76,6 +119,34 @@ func (mr *MockProductInterfaceMockRecorder) Enable() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MockProductInterface)(nil).Enable))
 }
 
+// Events mocks base method.
+func (m *MockProductInterface) Events() []application.DomainEvent {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Events")
+	ret0, _ := ret[0].([]application.DomainEvent)
+	return ret0
+}
+
+// Events indicates an expected call of Events.
+func (mr *MockProductInterfaceMockRecorder) Events() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Events", reflect.TypeOf((*MockProductInterface)(nil).Events))
+}
+
+// GetDeletedAt mocks base method.
+func (m *MockProductInterface) GetDeletedAt() *time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedAt")
+	ret0, _ := ret[0].(*time.Time)
+	return ret0
+}
+
+// GetDeletedAt indicates an expected call of GetDeletedAt.
+func (mr *MockProductInterfaceMockRecorder) GetDeletedAt() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedAt", reflect.TypeOf((*MockProductInterface)(nil).GetDeletedAt))
+}
+
 // GetId mocks base method.
 func (m *MockProductInterface) GetId() string {
 	m.ctrl.T.Helper()
@@ -105,10 +176,10 @@ func (mr *MockProductInterfaceMockRecorder) GetName() *gomock.Call {
 }
 
 // GetPrice mocks base method.
-func (m *MockProductInterface) GetPrice() float64 {
+func (m *MockProductInterface) GetPrice() application.Money {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPrice")
-	ret0, _ := ret[0].(float64)
+	ret0, _ := ret[0].(application.Money)
 	return ret0
 }
 
@@ -132,6 +203,20 @@ func (mr *MockProductInterfaceMockRecorder) GetStatus() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockProductInterface)(nil).GetStatus))
 }
 
+// GetVersion mocks base method.
+func (m *MockProductInterface) GetVersion() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersion")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetVersion indicates an expected call of GetVersion.
+func (mr *MockProductInterfaceMockRecorder) GetVersion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockProductInterface)(nil).GetVersion))
+}
+
 // IsValid mocks base method.
 func (m *MockProductInterface) IsValid() (bool, error) {
 	m.ctrl.T.Helper()
@@ -147,6 +232,32 @@ func (mr *MockProductInterfaceMockRecorder) IsValid() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsValid", reflect.TypeOf((*MockProductInterface)(nil).IsValid))
 }
 
+// Restore mocks base method.
+func (m *MockProductInterface) Restore() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockProductInterfaceMockRecorder) Restore() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockProductInterface)(nil).Restore))
+}
+
+// SetVersion mocks base method.
+func (m *MockProductInterface) SetVersion(version int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetVersion", version)
+}
+
+// SetVersion indicates an expected call of SetVersion.
+func (mr *MockProductInterfaceMockRecorder) SetVersion(version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVersion", reflect.TypeOf((*MockProductInterface)(nil).SetVersion), version)
+}
+
 // MockProductServiceInterface is a mock of ProductServiceInterface interface.
 type MockProductServiceInterface struct {
 	ctrl     *gomock.Controller
@@ -170,64 +281,256 @@ func (m *MockProductServiceInterface) EXPECT() *MockProductServiceInterfaceMockR
 	return m.recorder
 }
 
+// Archive mocks base method.
+func (m *MockProductServiceInterface) Archive(ctx context.Context, product application.ProductInterface) (application.ProductInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Archive", ctx, product)
+	ret0, _ := ret[0].(application.ProductInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockProductServiceInterfaceMockRecorder) Archive(ctx, product interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockProductServiceInterface)(nil).Archive), ctx, product)
+}
+
+// ChangeName mocks base method.
+func (m *MockProductServiceInterface) ChangeName(ctx context.Context, product application.ProductInterface, name string) (application.ProductInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeName", ctx, product, name)
+	ret0, _ := ret[0].(application.ProductInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeName indicates an expected call of ChangeName.
+func (mr *MockProductServiceInterfaceMockRecorder) ChangeName(ctx, product, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeName", reflect.TypeOf((*MockProductServiceInterface)(nil).ChangeName), ctx, product, name)
+}
+
+// ChangePrice mocks base method.
+func (m *MockProductServiceInterface) ChangePrice(ctx context.Context, product application.ProductInterface, price application.Money) (application.ProductInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePrice", ctx, product, price)
+	ret0, _ := ret[0].(application.ProductInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePrice indicates an expected call of ChangePrice.
+func (mr *MockProductServiceInterfaceMockRecorder) ChangePrice(ctx, product, price interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePrice", reflect.TypeOf((*MockProductServiceInterface)(nil).ChangePrice), ctx, product, price)
+}
+
 // Create mocks base method.
-func (m *MockProductServiceInterface) Create(name string, price float64) (application.ProductInterface, error) {
+func (m *MockProductServiceInterface) Create(ctx context.Context, name string, price application.Money) (application.ProductInterface, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", name, price)
+	ret := m.ctrl.Call(m, "Create", ctx, name, price)
 	ret0, _ := ret[0].(application.ProductInterface)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockProductServiceInterfaceMockRecorder) Create(name, price interface{}) *gomock.Call {
+func (mr *MockProductServiceInterfaceMockRecorder) Create(ctx, name, price interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductServiceInterface)(nil).Create), ctx, name, price)
+}
+
+// CreateMany mocks base method.
+func (m *MockProductServiceInterface) CreateMany(ctx context.Context, inputs []application.ProductInput, mode string) (application.BatchReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMany", ctx, inputs, mode)
+	ret0, _ := ret[0].(application.BatchReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMany indicates an expected call of CreateMany.
+func (mr *MockProductServiceInterfaceMockRecorder) CreateMany(ctx, inputs, mode interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductServiceInterface)(nil).Create), name, price)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMany", reflect.TypeOf((*MockProductServiceInterface)(nil).CreateMany), ctx, inputs, mode)
 }
 
 // Disable mocks base method.
-func (m *MockProductServiceInterface) Disable(product application.ProductInterface) (application.ProductInterface, error) {
+func (m *MockProductServiceInterface) Disable(ctx context.Context, product application.ProductInterface) (application.ProductInterface, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Disable", product)
+	ret := m.ctrl.Call(m, "Disable", ctx, product)
 	ret0, _ := ret[0].(application.ProductInterface)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Disable indicates an expected call of Disable.
-func (mr *MockProductServiceInterfaceMockRecorder) Disable(product interface{}) *gomock.Call {
+func (mr *MockProductServiceInterfaceMockRecorder) Disable(ctx, product interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockProductServiceInterface)(nil).Disable), product)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockProductServiceInterface)(nil).Disable), ctx, product)
+}
+
+// DisableMany mocks base method.
+func (m *MockProductServiceInterface) DisableMany(ctx context.Context, ids []string, mode string) (application.BatchReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableMany", ctx, ids, mode)
+	ret0, _ := ret[0].(application.BatchReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableMany indicates an expected call of DisableMany.
+func (mr *MockProductServiceInterfaceMockRecorder) DisableMany(ctx, ids, mode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableMany", reflect.TypeOf((*MockProductServiceInterface)(nil).DisableMany), ctx, ids, mode)
 }
 
 // Enable mocks base method.
-func (m *MockProductServiceInterface) Enable(product application.ProductInterface) (application.ProductInterface, error) {
+func (m *MockProductServiceInterface) Enable(ctx context.Context, product application.ProductInterface) (application.ProductInterface, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Enable", product)
+	ret := m.ctrl.Call(m, "Enable", ctx, product)
 	ret0, _ := ret[0].(application.ProductInterface)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Enable indicates an expected call of Enable.
-func (mr *MockProductServiceInterfaceMockRecorder) Enable(product interface{}) *gomock.Call {
+func (mr *MockProductServiceInterfaceMockRecorder) Enable(ctx, product interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MockProductServiceInterface)(nil).Enable), ctx, product)
+}
+
+// EnableMany mocks base method.
+func (m *MockProductServiceInterface) EnableMany(ctx context.Context, ids []string, mode string) (application.BatchReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableMany", ctx, ids, mode)
+	ret0, _ := ret[0].(application.BatchReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableMany indicates an expected call of EnableMany.
+func (mr *MockProductServiceInterfaceMockRecorder) EnableMany(ctx, ids, mode interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MockProductServiceInterface)(nil).Enable), product)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableMany", reflect.TypeOf((*MockProductServiceInterface)(nil).EnableMany), ctx, ids, mode)
+}
+
+// Export mocks base method.
+func (m *MockProductServiceInterface) Export(ctx context.Context, w io.Writer, query application.ProductQuery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, w, query)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockProductServiceInterfaceMockRecorder) Export(ctx, w, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockProductServiceInterface)(nil).Export), ctx, w, query)
 }
 
 // Get mocks base method.
-func (m *MockProductServiceInterface) Get(id string) (application.ProductInterface, error) {
+func (m *MockProductServiceInterface) Get(ctx context.Context, id string) (application.ProductInterface, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", id)
+	ret := m.ctrl.Call(m, "Get", ctx, id)
 	ret0, _ := ret[0].(application.ProductInterface)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockProductServiceInterfaceMockRecorder) Get(id interface{}) *gomock.Call {
+func (mr *MockProductServiceInterfaceMockRecorder) Get(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockProductServiceInterface)(nil).Get), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockProductServiceInterface)(nil).Get), ctx, id)
+}
+
+// History mocks base method.
+func (m *MockProductServiceInterface) History(ctx context.Context, id string) ([]application.ProductChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, id)
+	ret0, _ := ret[0].([]application.ProductChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockProductServiceInterfaceMockRecorder) History(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockProductServiceInterface)(nil).History), ctx, id)
+}
+
+// Import mocks base method.
+func (m *MockProductServiceInterface) Import(ctx context.Context, r io.Reader, dryRun bool) (application.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, r, dryRun)
+	ret0, _ := ret[0].(application.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockProductServiceInterfaceMockRecorder) Import(ctx, r, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockProductServiceInterface)(nil).Import), ctx, r, dryRun)
+}
+
+// List mocks base method.
+func (m *MockProductServiceInterface) List(ctx context.Context, query application.ProductQuery) (application.ProductPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, query)
+	ret0, _ := ret[0].(application.ProductPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockProductServiceInterfaceMockRecorder) List(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockProductServiceInterface)(nil).List), ctx, query)
+}
+
+// Purge mocks base method.
+func (m *MockProductServiceInterface) Purge(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockProductServiceInterfaceMockRecorder) Purge(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockProductServiceInterface)(nil).Purge), ctx, id)
+}
+
+// Restore mocks base method.
+func (m *MockProductServiceInterface) Restore(ctx context.Context, id string) (application.ProductInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(application.ProductInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockProductServiceInterfaceMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockProductServiceInterface)(nil).Restore), ctx, id)
+}
+
+// WithinTransaction mocks base method.
+func (m *MockProductServiceInterface) WithinTransaction(ctx context.Context, fn func(context.Context, application.ProductServiceInterface) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTransaction indicates an expected call of WithinTransaction.
+func (mr *MockProductServiceInterfaceMockRecorder) WithinTransaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTransaction", reflect.TypeOf((*MockProductServiceInterface)(nil).WithinTransaction), ctx, fn)
 }
 
 // MockProductReaderInterface is a mock of ProductReaderInterface interface.
@@ -254,18 +557,63 @@ func (m *MockProductReaderInterface) EXPECT() *MockProductReaderInterfaceMockRec
 }
 
 // Get mocks base method.
-func (m *MockProductReaderInterface) Get(id string) (application.ProductInterface, error) {
+func (m *MockProductReaderInterface) Get(ctx context.Context, id string) (application.ProductInterface, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", id)
+	ret := m.ctrl.Call(m, "Get", ctx, id)
 	ret0, _ := ret[0].(application.ProductInterface)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockProductReaderInterfaceMockRecorder) Get(id interface{}) *gomock.Call {
+func (mr *MockProductReaderInterfaceMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockProductReaderInterface)(nil).Get), ctx, id)
+}
+
+// GetArchived mocks base method.
+func (m *MockProductReaderInterface) GetArchived(ctx context.Context, id string) (application.ProductInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArchived", ctx, id)
+	ret0, _ := ret[0].(application.ProductInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArchived indicates an expected call of GetArchived.
+func (mr *MockProductReaderInterfaceMockRecorder) GetArchived(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArchived", reflect.TypeOf((*MockProductReaderInterface)(nil).GetArchived), ctx, id)
+}
+
+// History mocks base method.
+func (m *MockProductReaderInterface) History(ctx context.Context, id string) ([]application.ProductChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, id)
+	ret0, _ := ret[0].([]application.ProductChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockProductReaderInterfaceMockRecorder) History(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockProductReaderInterface)(nil).History), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockProductReaderInterface) List(ctx context.Context, query application.ProductQuery) (application.ProductPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, query)
+	ret0, _ := ret[0].(application.ProductPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockProductReaderInterfaceMockRecorder) List(ctx, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockProductReaderInterface)(nil).Get), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockProductReaderInterface)(nil).List), ctx, query)
 }
 
 // MockProductWriterInterface is a mock of ProductWriterInterface interface.
@@ -291,19 +639,33 @@ func (m *MockProductWriterInterface) EXPECT() *MockProductWriterInterfaceMockRec
 	return m.recorder
 }
 
+// Delete mocks base method.
+func (m *MockProductWriterInterface) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProductWriterInterfaceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductWriterInterface)(nil).Delete), ctx, id)
+}
+
 // Save mocks base method.
-func (m *MockProductWriterInterface) Save(product application.ProductInterface) (application.ProductInterface, error) {
+func (m *MockProductWriterInterface) Save(ctx context.Context, product application.ProductInterface) (application.ProductInterface, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", product)
+	ret := m.ctrl.Call(m, "Save", ctx, product)
 	ret0, _ := ret[0].(application.ProductInterface)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Save indicates an expected call of Save.
-func (mr *MockProductWriterInterfaceMockRecorder) Save(product interface{}) *gomock.Call {
+func (mr *MockProductWriterInterfaceMockRecorder) Save(ctx, product interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockProductWriterInterface)(nil).Save), product)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockProductWriterInterface)(nil).Save), ctx, product)
 }
 
 // MockProductPersistenceInterface is a mock of ProductPersistenceInterface interface.
@@ -329,32 +691,128 @@ func (m *MockProductPersistenceInterface) EXPECT() *MockProductPersistenceInterf
 	return m.recorder
 }
 
+// Delete mocks base method.
+func (m *MockProductPersistenceInterface) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProductPersistenceInterfaceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductPersistenceInterface)(nil).Delete), ctx, id)
+}
+
 // Get mocks base method.
-func (m *MockProductPersistenceInterface) Get(id string) (application.ProductInterface, error) {
+func (m *MockProductPersistenceInterface) Get(ctx context.Context, id string) (application.ProductInterface, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", id)
+	ret := m.ctrl.Call(m, "Get", ctx, id)
 	ret0, _ := ret[0].(application.ProductInterface)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockProductPersistenceInterfaceMockRecorder) Get(id interface{}) *gomock.Call {
+func (mr *MockProductPersistenceInterfaceMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockProductPersistenceInterface)(nil).Get), ctx, id)
+}
+
+// GetArchived mocks base method.
+func (m *MockProductPersistenceInterface) GetArchived(ctx context.Context, id string) (application.ProductInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArchived", ctx, id)
+	ret0, _ := ret[0].(application.ProductInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArchived indicates an expected call of GetArchived.
+func (mr *MockProductPersistenceInterfaceMockRecorder) GetArchived(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockProductPersistenceInterface)(nil).Get), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArchived", reflect.TypeOf((*MockProductPersistenceInterface)(nil).GetArchived), ctx, id)
+}
+
+// History mocks base method.
+func (m *MockProductPersistenceInterface) History(ctx context.Context, id string) ([]application.ProductChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, id)
+	ret0, _ := ret[0].([]application.ProductChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockProductPersistenceInterfaceMockRecorder) History(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockProductPersistenceInterface)(nil).History), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockProductPersistenceInterface) List(ctx context.Context, query application.ProductQuery) (application.ProductPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, query)
+	ret0, _ := ret[0].(application.ProductPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockProductPersistenceInterfaceMockRecorder) List(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockProductPersistenceInterface)(nil).List), ctx, query)
 }
 
 // Save mocks base method.
-func (m *MockProductPersistenceInterface) Save(product application.ProductInterface) (application.ProductInterface, error) {
+func (m *MockProductPersistenceInterface) Save(ctx context.Context, product application.ProductInterface) (application.ProductInterface, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", product)
+	ret := m.ctrl.Call(m, "Save", ctx, product)
 	ret0, _ := ret[0].(application.ProductInterface)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Save indicates an expected call of Save.
-func (mr *MockProductPersistenceInterfaceMockRecorder) Save(product interface{}) *gomock.Call {
+func (mr *MockProductPersistenceInterfaceMockRecorder) Save(ctx, product interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockProductPersistenceInterface)(nil).Save), ctx, product)
+}
+
+// MockProductTransactionInterface is a mock of ProductTransactionInterface interface.
+type MockProductTransactionInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductTransactionInterfaceMockRecorder
+}
+
+// MockProductTransactionInterfaceMockRecorder is the mock recorder for MockProductTransactionInterface.
+type MockProductTransactionInterfaceMockRecorder struct {
+	mock *MockProductTransactionInterface
+}
+
+// NewMockProductTransactionInterface creates a new mock instance.
+func NewMockProductTransactionInterface(ctrl *gomock.Controller) *MockProductTransactionInterface {
+	mock := &MockProductTransactionInterface{ctrl: ctrl}
+	mock.recorder = &MockProductTransactionInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductTransactionInterface) EXPECT() *MockProductTransactionInterfaceMockRecorder {
+	return m.recorder
+}
+
+// WithinTransaction mocks base method.
+func (m *MockProductTransactionInterface) WithinTransaction(ctx context.Context, fn func(context.Context, application.ProductPersistenceInterface) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTransaction indicates an expected call of WithinTransaction.
+func (mr *MockProductTransactionInterfaceMockRecorder) WithinTransaction(ctx, fn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockProductPersistenceInterface)(nil).Save), product)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTransaction", reflect.TypeOf((*MockProductTransactionInterface)(nil).WithinTransaction), ctx, fn)
 }
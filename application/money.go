@@ -0,0 +1,119 @@
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+)
+
+const DEFAULT_CURRENCY = "BRL"
+
+var currencyExponents = map[string]int{
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+func ParseMoney(value, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	if !govalidator.IsISO4217(currency) {
+		return Money{}, NewValidationError("currency", "The currency must be a valid ISO 4217 code", ErrInvalidPrice)
+	}
+
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "-"), "+")
+
+	units, fraction, _ := strings.Cut(value, ".")
+	exponent := CurrencyExponent(currency)
+	if (units == "" && fraction == "") || !isDigits(units) || !isDigits(fraction) {
+		return Money{}, NewValidationError("price", fmt.Sprintf("The price %q is not a valid amount", value), ErrInvalidPrice)
+	}
+	if len(fraction) > exponent {
+		return Money{}, NewValidationError("price", fmt.Sprintf("The price must have at most %d decimal places for %s", exponent, currency), ErrInvalidPrice)
+	}
+
+	amount, err := strconv.ParseInt(units+fraction+strings.Repeat("0", exponent-len(fraction)), 10, 64)
+	if err != nil {
+		return Money{}, NewValidationError("price", fmt.Sprintf("The price %q is not a valid amount", value), ErrInvalidPrice)
+	}
+	if negative {
+		amount = -amount
+	}
+	return NewMoney(amount, currency), nil
+}
+
+func CurrencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[strings.ToUpper(currency)]; ok {
+		return exponent
+	}
+	return 2
+}
+
+func (m Money) IsValid() (bool, error) {
+	if !govalidator.IsISO4217(m.Currency) {
+		return false, NewValidationError("currency", "The currency must be a valid ISO 4217 code", ErrInvalidPrice)
+	}
+	return true, nil
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, NewValidationError("currency", fmt.Sprintf("Cannot add %s to %s", other.Currency, m.Currency), ErrInvalidPrice)
+	}
+	return NewMoney(m.Amount+other.Amount, m.Currency), nil
+}
+
+func (m Money) Decimal() string {
+	exponent := CurrencyExponent(m.Currency)
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := strconv.FormatInt(amount, 10)
+	if exponent == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package application_test
+
+import (
+	"testing"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		currency string
+		expected application.Money
+		err      string
+	}{
+		{name: "Two decimal places", value: "19.99", currency: "BRL", expected: application.NewMoney(1999, "BRL")},
+		{name: "One decimal place", value: "19.9", currency: "usd", expected: application.NewMoney(1990, "USD")},
+		{name: "Integer amount", value: "19", currency: "BRL", expected: application.NewMoney(1900, "BRL")},
+		{name: "Negative amount", value: "-0.5", currency: "BRL", expected: application.NewMoney(-50, "BRL")},
+		{name: "Currency without minor units", value: "500", currency: "JPY", expected: application.NewMoney(500, "JPY")},
+		{name: "Currency with three decimal places", value: "1.005", currency: "KWD", expected: application.NewMoney(1005, "KWD")},
+		{name: "Too many decimal places", value: "19.999", currency: "BRL", err: "The price must have at most 2 decimal places for BRL"},
+		{name: "Invalid amount", value: "1e3", currency: "BRL", err: `The price "1e3" is not a valid amount`},
+		{name: "Empty amount", value: "", currency: "BRL", err: `The price "" is not a valid amount`},
+		{name: "Invalid currency", value: "10", currency: "ABC", err: "The currency must be a valid ISO 4217 code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := application.ParseMoney(tt.value, tt.currency)
+			if tt.err != "" {
+				assert.Equal(t, tt.err, err.Error())
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	assert.Equal(t, "19.99 BRL", application.NewMoney(1999, "BRL").String())
+	assert.Equal(t, "0.05 BRL", application.NewMoney(5, "BRL").String())
+	assert.Equal(t, "-1.50 USD", application.NewMoney(-150, "USD").String())
+	assert.Equal(t, "500 JPY", application.NewMoney(500, "JPY").String())
+	assert.Equal(t, "1.005 KWD", application.NewMoney(1005, "KWD").String())
+}
+
+func TestMoneyAdd(t *testing.T) {
+	total := application.NewMoney(0, "BRL")
+	for i := 0; i < 10; i++ {
+		var err error
+		total, err = total.Add(application.NewMoney(10, "BRL"))
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, "1.00 BRL", total.String())
+
+	_, err := total.Add(application.NewMoney(10, "USD"))
+	assert.Equal(t, "Cannot add USD to BRL", err.Error())
+}
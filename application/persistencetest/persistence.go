@@ -0,0 +1,363 @@
+// Package persistencetest checks that an application.ProductPersistenceInterface
+// implementation behaves like db.ProductDb, so adapters can be swapped freely.
+package persistencetest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/stretchr/testify/assert"
+)
+
+// Run calls newPersistence once per behaviour, so every subtest starts from
+// an empty catalog.
+func Run(t *testing.T, newPersistence func(t *testing.T) application.ProductPersistenceInterface) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, persistence application.ProductPersistenceInterface)
+	}{
+		{"Get missing", testGetMissing},
+		{"Create", testCreate},
+		{"Update", testUpdate},
+		{"Concurrent saves", testConcurrentSaves},
+		{"Status round trip", testStatusRoundTrip},
+		{"Price precision", testPricePrecision},
+		{"List", testList},
+		{"Delete", testDelete},
+		{"Stale save after archive", testStaleSaveAfterArchive},
+		{"Rolled back save", testRolledBackSave},
+		{"History", testHistory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newPersistence(t))
+		})
+	}
+}
+
+func save(t *testing.T, persistence application.ProductPersistenceInterface, product application.ProductInterface) application.ProductInterface {
+	t.Helper()
+	result, err := persistence.Save(context.Background(), product)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	product.ClearEvents()
+	return result
+}
+
+func get(t *testing.T, persistence application.ProductPersistenceInterface, id string) application.ProductInterface {
+	t.Helper()
+	product, err := persistence.Get(context.Background(), id)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return product
+}
+
+func testGetMissing(t *testing.T, persistence application.ProductPersistenceInterface) {
+	ctx := context.Background()
+
+	product, err := persistence.Get(ctx, "missing")
+	assert.ErrorIs(t, err, application.ErrProductNotFound)
+	assert.Nil(t, product)
+
+	_, err = persistence.GetArchived(ctx, "missing")
+	assert.ErrorIs(t, err, application.ErrProductNotFound)
+
+	_, err = persistence.History(ctx, "missing")
+	assert.ErrorIs(t, err, application.ErrProductNotFound)
+}
+
+func testCreate(t *testing.T, persistence application.ProductPersistenceInterface) {
+	product := application.NewProduct("Product 1", application.NewMoney(1999, application.DEFAULT_CURRENCY))
+	result := save(t, persistence, product)
+	assert.Equal(t, 1, result.GetVersion())
+
+	stored := get(t, persistence, product.GetId())
+	assert.Equal(t, product.GetId(), stored.GetId())
+	assert.Equal(t, "Product 1", stored.GetName())
+	assert.Equal(t, application.NewMoney(1999, application.DEFAULT_CURRENCY), stored.GetPrice())
+	assert.Equal(t, application.DISABLED, stored.GetStatus())
+	assert.Nil(t, stored.GetDeletedAt())
+	assert.Equal(t, 1, stored.GetVersion())
+	assert.Empty(t, stored.Events())
+
+	duplicate := &application.Product{Id: product.GetId(), Name: "Product 2", Price: application.NewMoney(1, application.DEFAULT_CURRENCY), Status: application.DISABLED}
+	_, err := persistence.Save(context.Background(), duplicate)
+	assert.ErrorIs(t, err, application.ErrConcurrentModification)
+	assert.Equal(t, "Product 1", get(t, persistence, product.GetId()).GetName())
+}
+
+func testUpdate(t *testing.T, persistence application.ProductPersistenceInterface) {
+	product := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+	save(t, persistence, product)
+
+	stored := get(t, persistence, product.GetId())
+	assert.Nil(t, stored.ChangeName("Product 1b"))
+	assert.Nil(t, stored.ChangePrice(application.NewMoney(1500, application.DEFAULT_CURRENCY)))
+	assert.Nil(t, stored.Enable())
+	result := save(t, persistence, stored)
+	assert.Equal(t, 2, result.GetVersion())
+
+	stored = get(t, persistence, product.GetId())
+	assert.Equal(t, "Product 1b", stored.GetName())
+	assert.Equal(t, application.NewMoney(1500, application.DEFAULT_CURRENCY), stored.GetPrice())
+	assert.Equal(t, application.ENABLED, stored.GetStatus())
+	assert.Equal(t, 2, stored.GetVersion())
+
+	result = save(t, persistence, stored)
+	assert.Equal(t, 3, result.GetVersion())
+}
+
+func testConcurrentSaves(t *testing.T, persistence application.ProductPersistenceInterface) {
+	product := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+	save(t, persistence, product)
+
+	const writers = 8
+	copies := make([]application.ProductInterface, writers)
+	for i := range copies {
+		copies[i] = get(t, persistence, product.GetId())
+		assert.Nil(t, copies[i].ChangePrice(application.NewMoney(int64(2000+i), application.DEFAULT_CURRENCY)))
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+	for i, copy := range copies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = persistence.Save(context.Background(), copy)
+		}()
+	}
+	wg.Wait()
+
+	winner := -1
+	for i, err := range errs {
+		if err == nil {
+			assert.Equal(t, -1, winner, "more than one stale save succeeded")
+			winner = i
+			continue
+		}
+		assert.True(t, errors.Is(err, application.ErrConcurrentModification), "unexpected error: %v", err)
+	}
+	if !assert.NotEqual(t, -1, winner, "no save succeeded") {
+		return
+	}
+
+	stored := get(t, persistence, product.GetId())
+	assert.Equal(t, copies[winner].GetPrice(), stored.GetPrice())
+	assert.Equal(t, 2, stored.GetVersion())
+}
+
+func testStatusRoundTrip(t *testing.T, persistence application.ProductPersistenceInterface) {
+	ctx := context.Background()
+	product := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+	save(t, persistence, product)
+
+	assert.Nil(t, product.Enable())
+	save(t, persistence, product)
+	assert.Equal(t, application.ENABLED, get(t, persistence, product.GetId()).GetStatus())
+
+	assert.Nil(t, product.ChangePrice(application.NewMoney(0, application.DEFAULT_CURRENCY)))
+	assert.Nil(t, product.Disable())
+	save(t, persistence, product)
+	assert.Equal(t, application.DISABLED, get(t, persistence, product.GetId()).GetStatus())
+
+	assert.Nil(t, product.Archive())
+	save(t, persistence, product)
+	_, err := persistence.Get(ctx, product.GetId())
+	assert.ErrorIs(t, err, application.ErrProductNotFound)
+	archived, err := persistence.GetArchived(ctx, product.GetId())
+	assert.Nil(t, err)
+	if assert.NotNil(t, archived) {
+		assert.Equal(t, application.ARCHIVED, archived.GetStatus())
+		if assert.NotNil(t, archived.GetDeletedAt()) {
+			assert.True(t, product.GetDeletedAt().Equal(*archived.GetDeletedAt()))
+		}
+	}
+
+	assert.Nil(t, product.Restore())
+	save(t, persistence, product)
+	restored := get(t, persistence, product.GetId())
+	assert.Equal(t, application.DISABLED, restored.GetStatus())
+	assert.Nil(t, restored.GetDeletedAt())
+	_, err = persistence.GetArchived(ctx, product.GetId())
+	assert.ErrorIs(t, err, application.ErrProductNotFound)
+}
+
+func testPricePrecision(t *testing.T, persistence application.ProductPersistenceInterface) {
+	prices := []application.Money{
+		application.NewMoney(1, application.DEFAULT_CURRENCY),
+		application.NewMoney(1999, "USD"),
+		application.NewMoney(123456789012345, "EUR"),
+		application.NewMoney(1000, "JPY"),
+		application.NewMoney(1001, "KWD"),
+	}
+	for _, price := range prices {
+		product := application.NewProduct("Product "+price.String(), price)
+		save(t, persistence, product)
+		assert.Equal(t, price, get(t, persistence, product.GetId()).GetPrice())
+	}
+}
+
+func testList(t *testing.T, persistence application.ProductPersistenceInterface) {
+	ctx := context.Background()
+	products := []*application.Product{
+		{Id: "a", Name: "Product C", Price: application.NewMoney(3000, application.DEFAULT_CURRENCY), Status: application.DISABLED},
+		{Id: "b", Name: "Special_Product A", Price: application.NewMoney(500, application.DEFAULT_CURRENCY), Status: application.ENABLED},
+		{Id: "c", Name: "Product B", Price: application.NewMoney(2000, application.DEFAULT_CURRENCY), Status: application.ENABLED},
+		{Id: "d", Name: "Product D", Price: application.NewMoney(2000, application.DEFAULT_CURRENCY), Status: application.ENABLED},
+	}
+	for _, product := range products {
+		save(t, persistence, product)
+	}
+	assert.Nil(t, products[0].Archive())
+	save(t, persistence, products[0])
+
+	ids := func(page application.ProductPage) []string {
+		result := []string{}
+		for _, product := range page.Products {
+			result = append(result, product.GetId())
+		}
+		return result
+	}
+
+	page, err := persistence.List(ctx, application.ProductQuery{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"c", "d", "b"}, ids(page))
+	assert.Empty(t, page.NextCursor)
+
+	query := application.ProductQuery{SortBy: application.SORT_BY_PRICE, Desc: true, Limit: 2}
+	page, err = persistence.List(ctx, query)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"d", "c"}, ids(page))
+	query.Cursor = page.NextCursor
+	page, err = persistence.List(ctx, query)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"b"}, ids(page))
+	assert.Empty(t, page.NextCursor)
+
+	minPrice := application.NewMoney(1000, application.DEFAULT_CURRENCY)
+	page, err = persistence.List(ctx, application.ProductQuery{Status: application.ENABLED, MinPrice: &minPrice})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"c", "d"}, ids(page))
+
+	page, err = persistence.List(ctx, application.ProductQuery{Name: "special_"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"b"}, ids(page))
+
+	page, err = persistence.List(ctx, application.ProductQuery{Status: application.ARCHIVED})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a"}, ids(page))
+
+	_, err = persistence.List(ctx, application.ProductQuery{Status: "sold"})
+	var validationErr *application.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func testDelete(t *testing.T, persistence application.ProductPersistenceInterface) {
+	ctx := context.Background()
+	product := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+	save(t, persistence, product)
+
+	assert.Nil(t, product.ChangePrice(application.NewMoney(1500, application.DEFAULT_CURRENCY)))
+	save(t, persistence, product)
+
+	assert.Nil(t, persistence.Delete(ctx, product.GetId()))
+	_, err := persistence.Get(ctx, product.GetId())
+	assert.ErrorIs(t, err, application.ErrProductNotFound)
+	// A purge leaves no trace of the product's names and prices.
+	changes, err := persistence.History(ctx, product.GetId())
+	assert.ErrorIs(t, err, application.ErrProductNotFound)
+	assert.Empty(t, changes)
+	assert.ErrorIs(t, persistence.Delete(ctx, product.GetId()), application.ErrProductNotFound)
+
+	assert.Nil(t, product.ChangeName("Product 1b"))
+	_, err = persistence.Save(ctx, product)
+	assert.ErrorIs(t, err, application.ErrConcurrentModification)
+}
+
+func testStaleSaveAfterArchive(t *testing.T, persistence application.ProductPersistenceInterface) {
+	ctx := context.Background()
+	product := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+	save(t, persistence, product)
+
+	stale := get(t, persistence, product.GetId())
+	assert.Nil(t, product.Archive())
+	save(t, persistence, product)
+
+	assert.Nil(t, stale.ChangeName("Product 1b"))
+	_, err := persistence.Save(ctx, stale)
+	assert.ErrorIs(t, err, application.ErrConcurrentModification)
+
+	archived, err := persistence.GetArchived(ctx, product.GetId())
+	assert.Nil(t, err)
+	if assert.NotNil(t, archived) {
+		assert.Equal(t, "Product 1", archived.GetName())
+		assert.Equal(t, application.ARCHIVED, archived.GetStatus())
+		assert.Equal(t, 2, archived.GetVersion())
+	}
+	_, err = persistence.Get(ctx, product.GetId())
+	assert.ErrorIs(t, err, application.ErrProductNotFound)
+}
+
+// A save that does not commit leaves the product at the version it was
+// loaded at, so it can be saved again.
+func testRolledBackSave(t *testing.T, persistence application.ProductPersistenceInterface) {
+	transaction, ok := persistence.(application.ProductTransactionInterface)
+	if !ok {
+		t.Skip("the persistence does not support transactions")
+	}
+	ctx := context.Background()
+	product := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+	save(t, persistence, product)
+
+	assert.Nil(t, product.Enable())
+	err := transaction.WithinTransaction(ctx, func(ctx context.Context, tx application.ProductPersistenceInterface) error {
+		if _, err := tx.Save(ctx, product); err != nil {
+			return err
+		}
+		return errors.New("abort")
+	})
+	assert.EqualError(t, err, "abort")
+	assert.Equal(t, 1, product.GetVersion())
+	stored := get(t, persistence, product.GetId())
+	assert.Equal(t, application.DISABLED, stored.GetStatus())
+	assert.Equal(t, 1, stored.GetVersion())
+
+	result := save(t, persistence, product)
+	assert.Equal(t, 2, result.GetVersion())
+	assert.Equal(t, application.ENABLED, get(t, persistence, product.GetId()).GetStatus())
+}
+
+func testHistory(t *testing.T, persistence application.ProductPersistenceInterface) {
+	product := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+	_, err := persistence.Save(application.WithActor(context.Background(), "alice"), product)
+	assert.Nil(t, err)
+	assert.Nil(t, product.ChangePrice(application.NewMoney(1500, application.DEFAULT_CURRENCY)))
+	_, err = persistence.Save(application.WithActor(context.Background(), "bob"), product)
+	assert.Nil(t, err)
+
+	changes, err := persistence.History(context.Background(), product.GetId())
+	assert.Nil(t, err)
+	if !assert.Len(t, changes, 4) {
+		return
+	}
+	for _, change := range changes[:3] {
+		assert.Equal(t, 1, change.Version)
+		assert.Nil(t, change.OldValue)
+		assert.Equal(t, "alice", change.Actor)
+	}
+	last := changes[3]
+	assert.Equal(t, 2, last.Version)
+	assert.Equal(t, "price", last.Field)
+	if assert.NotNil(t, last.OldValue) {
+		assert.Equal(t, "10.00 BRL", *last.OldValue)
+	}
+	assert.Equal(t, "15.00 BRL", last.NewValue)
+	assert.Equal(t, "bob", last.Actor)
+}
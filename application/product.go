@@ -1,7 +1,10 @@
 package application
 
 import (
-	"errors"
+	"context"
+	"io"
+	"strings"
+	"time"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/google/uuid"
@@ -18,23 +21,48 @@ type ProductInterface interface {
 	GetId() string
 	GetName() string
 	GetStatus() string
-	GetPrice() float64
-	ChangePrice(price float64) error
+	GetPrice() Money
+	ChangePrice(price Money) error
+	ChangeName(name string) error
+	Archive() error
+	Restore() error
+	GetDeletedAt() *time.Time
+	GetVersion() int
+	SetVersion(version int)
+	Events() []DomainEvent
+	ClearEvents()
 }
 
 type ProductServiceInterface interface {
-	Get(id string) (ProductInterface, error)
-	Create(name string, price float64) (ProductInterface, error)
-	Enable(product ProductInterface) (ProductInterface, error)
-	Disable(product ProductInterface) (ProductInterface, error)
+	Get(ctx context.Context, id string) (ProductInterface, error)
+	List(ctx context.Context, query ProductQuery) (ProductPage, error)
+	Import(ctx context.Context, r io.Reader, dryRun bool) (ImportReport, error)
+	Export(ctx context.Context, w io.Writer, query ProductQuery) error
+	Create(ctx context.Context, name string, price Money) (ProductInterface, error)
+	CreateMany(ctx context.Context, inputs []ProductInput, mode string) (BatchReport, error)
+	Enable(ctx context.Context, product ProductInterface) (ProductInterface, error)
+	EnableMany(ctx context.Context, ids []string, mode string) (BatchReport, error)
+	Disable(ctx context.Context, product ProductInterface) (ProductInterface, error)
+	DisableMany(ctx context.Context, ids []string, mode string) (BatchReport, error)
+	ChangeName(ctx context.Context, product ProductInterface, name string) (ProductInterface, error)
+	ChangePrice(ctx context.Context, product ProductInterface, price Money) (ProductInterface, error)
+	Archive(ctx context.Context, product ProductInterface) (ProductInterface, error)
+	Restore(ctx context.Context, id string) (ProductInterface, error)
+	Purge(ctx context.Context, id string) error
+	History(ctx context.Context, id string) ([]ProductChange, error)
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context, service ProductServiceInterface) error) error
 }
 
 type ProductReaderInterface interface {
-	Get(id string) (ProductInterface, error)
+	Get(ctx context.Context, id string) (ProductInterface, error)
+	GetArchived(ctx context.Context, id string) (ProductInterface, error)
+	List(ctx context.Context, query ProductQuery) (ProductPage, error)
+	History(ctx context.Context, id string) ([]ProductChange, error)
 }
 
 type ProductWriterInterface interface {
-	Save(product ProductInterface) (ProductInterface, error)
+	Save(ctx context.Context, product ProductInterface) (ProductInterface, error)
+	Delete(ctx context.Context, id string) error
 }
 
 type ProductPersistenceInterface interface {
@@ -42,55 +70,80 @@ type ProductPersistenceInterface interface {
 	ProductReaderInterface
 }
 
+type ProductTransactionInterface interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context, persistence ProductPersistenceInterface) error) error
+}
+
 const (
 	DISABLED = "disabled"
 	ENABLED  = "enabled"
+	ARCHIVED = "archived"
 )
 
 type Product struct {
-	Price  float64 `valid:"float,optional"`
-	Id     string  `valid:"uuid"`
-	Name   string  `valid:"required"`
-	Status string  `valid:"required,in(disabled|enabled)"`
+	Price     Money      `valid:"-"`
+	DeletedAt *time.Time `valid:"-"`
+	Version   int        `valid:"-"`
+	Id        string     `valid:"uuid"`
+	Name      string     `valid:"required"`
+	Status    string     `valid:"required,in(disabled|enabled|archived)"`
+	events    []DomainEvent
 }
 
-func NewProduct(name string, price float64) *Product {
-	return &Product{
-		Id:     uuid.NewString(),
+func NewProduct(name string, price Money) *Product {
+	return newProduct(uuid.NewString(), name, price)
+}
+
+func newProduct(id, name string, price Money) *Product {
+	product := &Product{
+		Id:     id,
 		Name:   name,
 		Status: DISABLED,
 		Price:  price,
 	}
+	product.record(ProductCreated{ProductEvent: newProductEvent(product.Id), Name: name, Price: price})
+	return product
 }
 
 func (p *Product) IsValid() (bool, error) {
-	if p.Price < 0 {
-		return false, errors.New("The price must be greater than or equal to zero")
+	if p.Price.IsNegative() {
+		return false, NewValidationError("price", "The price must be greater than or equal to zero", ErrInvalidPrice)
+	}
+	if valid, err := p.Price.IsValid(); !valid {
+		return false, err
 	}
 	_, err := govalidator.ValidateStruct(p)
 	if err != nil {
-		return false, err
+		return false, newStructValidationError(err)
 	}
 	return true, nil
 }
 
 func (p *Product) Enable() error {
-	if p.Price <= 0 {
-		return errors.New("The price must be greater than zero to enable the product")
+	if p.Status == ARCHIVED {
+		return &TransitionError{From: p.Status, To: ENABLED, Reason: "The product must be restored before being enabled"}
+	}
+	if !p.Price.IsPositive() {
+		return &TransitionError{From: p.Status, To: ENABLED, Reason: "The price must be greater than zero to enable the product"}
 	}
 
 	if p.Status == DISABLED {
 		p.Status = ENABLED
+		p.record(ProductEnabled{ProductEvent: newProductEvent(p.Id)})
 	}
 	return nil
 }
 
 func (p *Product) Disable() error {
-	if p.Price > 0 {
-		return errors.New("The price must be zero to disable the product")
+	if p.Status == ARCHIVED {
+		return &TransitionError{From: p.Status, To: DISABLED, Reason: "The product must be restored before being disabled"}
+	}
+	if p.Price.IsPositive() {
+		return &TransitionError{From: p.Status, To: DISABLED, Reason: "The price must be zero to disable the product"}
 	}
 	if p.Status == ENABLED {
 		p.Status = DISABLED
+		p.record(ProductDisabled{ProductEvent: newProductEvent(p.Id)})
 	}
 	return nil
 }
@@ -107,14 +160,86 @@ func (p *Product) GetStatus() string {
 	return p.Status
 }
 
-func (p *Product) GetPrice() float64 {
+func (p *Product) GetPrice() Money {
 	return p.Price
 }
 
-func (p *Product) ChangePrice(price float64) error {
-	if price < 0 {
-		return errors.New("The price must be greater than or equal to zero")
+func (p *Product) GetDeletedAt() *time.Time {
+	return p.DeletedAt
+}
+
+func (p *Product) GetVersion() int {
+	return p.Version
+}
+
+func (p *Product) SetVersion(version int) {
+	p.Version = version
+}
+
+func (p *Product) ChangePrice(price Money) error {
+	if price.IsNegative() {
+		return NewValidationError("price", "The price must be greater than or equal to zero", ErrInvalidPrice)
+	}
+	if valid, err := price.IsValid(); !valid {
+		return err
+	}
+	if price != p.Price {
+		p.record(ProductPriceChanged{ProductEvent: newProductEvent(p.Id), OldPrice: p.Price, NewPrice: price})
 	}
 	p.Price = price
 	return nil
 }
+
+func (p *Product) ChangeName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return NewValidationError("name", "The name must not be empty", nil)
+	}
+	if name != p.Name {
+		p.record(ProductRenamed{ProductEvent: newProductEvent(p.Id), OldName: p.Name, NewName: name})
+	}
+	p.Name = name
+	return nil
+}
+
+func (p *Product) Archive() error {
+	if p.Status == ARCHIVED {
+		return &TransitionError{From: p.Status, To: ARCHIVED, Reason: "The product is already archived"}
+	}
+	deletedAt := time.Now().UTC()
+	p.Status = ARCHIVED
+	p.DeletedAt = &deletedAt
+	p.record(ProductArchived{ProductEvent: ProductEvent{ProductId: p.Id, OccurredAt: deletedAt}})
+	return nil
+}
+
+func (p *Product) Restore() error {
+	if p.Status != ARCHIVED {
+		return &TransitionError{From: p.Status, To: DISABLED, Reason: "Only archived products can be restored"}
+	}
+	p.Status = DISABLED
+	p.DeletedAt = nil
+	p.record(ProductRestored{ProductEvent: newProductEvent(p.Id)})
+	return nil
+}
+
+func (p *Product) Events() []DomainEvent {
+	return append([]DomainEvent(nil), p.events...)
+}
+
+func (p *Product) ClearEvents() {
+	p.events = nil
+}
+
+type eventRestorer interface {
+	restoreEvents(events []DomainEvent)
+}
+
+// restoreEvents puts back events that were cleared by a save that did not
+// commit, ahead of the ones recorded since.
+func (p *Product) restoreEvents(events []DomainEvent) {
+	p.events = append(append([]DomainEvent(nil), events...), p.events...)
+}
+
+func (p *Product) record(event DomainEvent) {
+	p.events = append(p.events, event)
+}
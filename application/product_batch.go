@@ -0,0 +1,150 @@
+package application
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	BATCH_ALL_OR_NOTHING = "all-or-nothing"
+	BATCH_BEST_EFFORT    = "best-effort"
+
+	OUTCOME_CREATED  = "created"
+	OUTCOME_ENABLED  = "enabled"
+	OUTCOME_DISABLED = "disabled"
+	OUTCOME_FAILED   = "failed"
+)
+
+var ErrBatchAborted = errors.New("The batch was rolled back because another item failed")
+
+var errBatchRollback = errors.New("batch rollback")
+
+type ProductInput struct {
+	Name  string
+	Price Money
+}
+
+type BatchItemResult struct {
+	Index   int
+	Id      string
+	Outcome string
+	Product ProductInterface
+	Err     error
+}
+
+type BatchReport struct {
+	Mode    string
+	Results []BatchItemResult
+}
+
+func (r BatchReport) Succeeded() int {
+	return len(r.Results) - r.Failed()
+}
+
+func (r BatchReport) Failed() int {
+	failed := 0
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+func (r BatchReport) abort() {
+	for i, result := range r.Results {
+		if result.Err == nil {
+			r.Results[i] = BatchItemResult{Index: result.Index, Id: result.Id, Outcome: OUTCOME_FAILED, Err: ErrBatchAborted}
+		}
+	}
+}
+
+type batchStep func(ctx context.Context, persistence ProductPersistenceInterface, index int) (string, ProductInterface, error)
+
+func (s *ProductService) CreateMany(ctx context.Context, inputs []ProductInput, mode string) (BatchReport, error) {
+	report, err := s.runBatch(ctx, mode, len(inputs), OUTCOME_CREATED, func(ctx context.Context, persistence ProductPersistenceInterface, index int) (string, ProductInterface, error) {
+		product := NewProduct(inputs[index].Name, inputs[index].Price)
+		if valid, err := product.IsValid(); !valid {
+			return "", nil, err
+		}
+		return product.GetId(), product, nil
+	})
+	// A product that was not created has no id to report.
+	for i, result := range report.Results {
+		if result.Err != nil {
+			report.Results[i].Id = ""
+		}
+	}
+	return report, err
+}
+
+func (s *ProductService) EnableMany(ctx context.Context, ids []string, mode string) (BatchReport, error) {
+	return s.runBatch(ctx, mode, len(ids), OUTCOME_ENABLED, func(ctx context.Context, persistence ProductPersistenceInterface, index int) (string, ProductInterface, error) {
+		product, err := persistence.Get(ctx, ids[index])
+		if err != nil {
+			return ids[index], nil, err
+		}
+		if err := product.Enable(); err != nil {
+			return ids[index], nil, err
+		}
+		return ids[index], product, nil
+	})
+}
+
+func (s *ProductService) DisableMany(ctx context.Context, ids []string, mode string) (BatchReport, error) {
+	return s.runBatch(ctx, mode, len(ids), OUTCOME_DISABLED, func(ctx context.Context, persistence ProductPersistenceInterface, index int) (string, ProductInterface, error) {
+		product, err := persistence.Get(ctx, ids[index])
+		if err != nil {
+			return ids[index], nil, err
+		}
+		if err := product.Disable(); err != nil {
+			return ids[index], nil, err
+		}
+		return ids[index], product, nil
+	})
+}
+
+// Every item is checked before anything is saved, so all-or-nothing batches
+// only depend on the transaction to undo saves that fail in storage.
+func (s *ProductService) runBatch(ctx context.Context, mode string, size int, outcome string, prepare batchStep) (BatchReport, error) {
+	if mode != BATCH_ALL_OR_NOTHING && mode != BATCH_BEST_EFFORT {
+		return BatchReport{}, NewValidationError("mode", "The batch mode must be all-or-nothing or best-effort", nil)
+	}
+
+	var report BatchReport
+	err := s.transaction(ctx, func(ctx context.Context, service *ProductService) error {
+		report = BatchReport{Mode: mode, Results: make([]BatchItemResult, size)}
+		products := make([]ProductInterface, size)
+		for i := range size {
+			id, product, err := prepare(ctx, service.ProductPersistence, i)
+			report.Results[i] = BatchItemResult{Index: i, Id: id, Outcome: OUTCOME_FAILED, Err: err}
+			products[i] = product
+		}
+		if mode == BATCH_ALL_OR_NOTHING && report.Failed() > 0 {
+			report.abort()
+			return errBatchRollback
+		}
+
+		for i, product := range products {
+			if product == nil {
+				continue
+			}
+			result, err := service.save(ctx, product)
+			if err != nil {
+				report.Results[i].Err = err
+				if mode == BATCH_ALL_OR_NOTHING {
+					report.abort()
+					return errBatchRollback
+				}
+				continue
+			}
+			report.Results[i].Outcome = outcome
+			report.Results[i].Product = result
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchRollback) {
+		return BatchReport{}, err
+	}
+	return report, nil
+}
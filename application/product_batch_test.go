@@ -0,0 +1,168 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/memory"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBatchService(ctrl *gomock.Controller) (*application.ProductService, *mock.MockProductPersistenceInterface) {
+	mockPersistence := mock.NewMockProductPersistenceInterface(ctrl)
+	mockTransaction := mock.NewMockProductTransactionInterface(ctrl)
+	mockTransaction.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context, application.ProductPersistenceInterface) error) error {
+			return fn(ctx, mockPersistence)
+		}).AnyTimes()
+	return application.NewProductService(transactionalPersistence{mockPersistence, mockTransaction}), mockPersistence
+}
+
+func TestProductServiceCreateMany(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	inputs := []application.ProductInput{
+		{Name: "Product 1", Price: application.NewMoney(1000, application.DEFAULT_CURRENCY)},
+		{Name: "", Price: application.NewMoney(2000, application.DEFAULT_CURRENCY)},
+		{Name: "Product 3", Price: application.NewMoney(-1, application.DEFAULT_CURRENCY)},
+		{Name: "Product 4", Price: application.NewMoney(4000, application.DEFAULT_CURRENCY)},
+	}
+
+	t.Run("Success - Best effort keeps the valid items", func(t *testing.T) {
+		service, mockPersistence := newBatchService(ctrl)
+		mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, product application.ProductInterface) (application.ProductInterface, error) {
+				return product, nil
+			}).Times(2)
+
+		report, err := service.CreateMany(context.Background(), inputs, application.BATCH_BEST_EFFORT)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, report.Succeeded())
+		assert.Equal(t, 2, report.Failed())
+		assert.Equal(t, application.OUTCOME_CREATED, report.Results[0].Outcome)
+		assert.Equal(t, "Product 1", report.Results[0].Product.GetName())
+		assert.Equal(t, application.OUTCOME_FAILED, report.Results[1].Outcome)
+		var validationErr *application.ValidationError
+		assert.ErrorAs(t, report.Results[1].Err, &validationErr)
+		assert.Empty(t, report.Results[1].Id)
+		assert.ErrorIs(t, report.Results[2].Err, application.ErrInvalidPrice)
+		assert.Empty(t, report.Results[2].Id)
+		assert.Equal(t, application.OUTCOME_CREATED, report.Results[3].Outcome)
+		assert.NotEmpty(t, report.Results[3].Id)
+	})
+
+	t.Run("Error - All or nothing saves nothing when an item is invalid", func(t *testing.T) {
+		service, _ := newBatchService(ctrl)
+
+		report, err := service.CreateMany(context.Background(), inputs, application.BATCH_ALL_OR_NOTHING)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, report.Succeeded())
+		assert.ErrorIs(t, report.Results[0].Err, application.ErrBatchAborted)
+		assert.Empty(t, report.Results[0].Id)
+		assert.ErrorIs(t, report.Results[2].Err, application.ErrInvalidPrice)
+	})
+
+	t.Run("Error - All or nothing rolls back when a save fails", func(t *testing.T) {
+		service, mockPersistence := newBatchService(ctrl)
+		gomock.InOrder(
+			mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil, nil),
+			mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil, application.ErrConcurrentModification),
+		)
+
+		report, err := service.CreateMany(context.Background(), []application.ProductInput{inputs[0], inputs[3]}, application.BATCH_ALL_OR_NOTHING)
+		assert.Nil(t, err)
+		assert.ErrorIs(t, report.Results[0].Err, application.ErrBatchAborted)
+		assert.Empty(t, report.Results[0].Id)
+		assert.ErrorIs(t, report.Results[1].Err, application.ErrConcurrentModification)
+		assert.Empty(t, report.Results[1].Id)
+	})
+
+	t.Run("Error - Unknown mode", func(t *testing.T) {
+		service, _ := newBatchService(ctrl)
+
+		_, err := service.CreateMany(context.Background(), inputs, "sometimes")
+		assert.Equal(t, "The batch mode must be all-or-nothing or best-effort", err.Error())
+	})
+
+	t.Run("Error - Transaction cannot start", func(t *testing.T) {
+		mockTransaction := mock.NewMockProductTransactionInterface(ctrl)
+		service := application.NewProductService(transactionalPersistence{mock.NewMockProductPersistenceInterface(ctrl), mockTransaction})
+		mockTransaction.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).Return(errors.New("database is locked"))
+
+		_, err := service.CreateMany(context.Background(), inputs, application.BATCH_BEST_EFFORT)
+		assert.EqualError(t, err, "database is locked")
+	})
+}
+
+func TestProductServiceEnableMany(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockPersistence := newBatchService(ctrl)
+	priced := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+	free := application.NewProduct("Product 2", application.NewMoney(0, application.DEFAULT_CURRENCY))
+
+	mockPersistence.EXPECT().Get(gomock.Any(), "1").Return(priced, nil)
+	mockPersistence.EXPECT().Get(gomock.Any(), "2").Return(free, nil)
+	mockPersistence.EXPECT().Get(gomock.Any(), "3").Return(nil, application.ErrProductNotFound)
+	mockPersistence.EXPECT().Save(gomock.Any(), priced).Return(priced, nil)
+
+	report, err := service.EnableMany(context.Background(), []string{"1", "2", "3"}, application.BATCH_BEST_EFFORT)
+	assert.Nil(t, err)
+	assert.Equal(t, application.OUTCOME_ENABLED, report.Results[0].Outcome)
+	assert.Equal(t, application.ENABLED, report.Results[0].Product.GetStatus())
+	assert.ErrorIs(t, report.Results[1].Err, application.ErrInvalidTransition)
+	assert.Equal(t, "3", report.Results[2].Id)
+	assert.ErrorIs(t, report.Results[2].Err, application.ErrProductNotFound)
+}
+
+func TestProductServiceDisableMany(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockPersistence := newBatchService(ctrl)
+	product := application.NewProduct("Product 1", application.NewMoney(0, application.DEFAULT_CURRENCY))
+	product.Status = application.ENABLED
+
+	mockPersistence.EXPECT().Get(gomock.Any(), "1").Return(product, nil)
+	mockPersistence.EXPECT().Save(gomock.Any(), product).Return(product, nil)
+
+	report, err := service.DisableMany(context.Background(), []string{"1"}, application.BATCH_ALL_OR_NOTHING)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, report.Succeeded())
+	assert.Equal(t, application.OUTCOME_DISABLED, report.Results[0].Outcome)
+	assert.Equal(t, application.DISABLED, product.GetStatus())
+}
+
+func TestProductServiceEnableManyInMemory(t *testing.T) {
+	service := application.NewProductService(memory.NewProductMemory())
+	product, err := service.Create(context.Background(), "Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+	assert.Nil(t, err)
+
+	t.Run("Error - All or nothing leaves the stored products untouched", func(t *testing.T) {
+		report, err := service.EnableMany(context.Background(), []string{product.GetId(), "missing"}, application.BATCH_ALL_OR_NOTHING)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, report.Succeeded())
+
+		stored, err := service.Get(context.Background(), product.GetId())
+		assert.Nil(t, err)
+		assert.Equal(t, application.DISABLED, stored.GetStatus())
+		assert.Equal(t, 1, stored.GetVersion())
+	})
+
+	t.Run("Success - Best effort saves the items that succeed", func(t *testing.T) {
+		report, err := service.EnableMany(context.Background(), []string{product.GetId(), "missing"}, application.BATCH_BEST_EFFORT)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, report.Succeeded())
+
+		stored, err := service.Get(context.Background(), product.GetId())
+		assert.Nil(t, err)
+		assert.Equal(t, application.ENABLED, stored.GetStatus())
+		assert.Equal(t, 2, stored.GetVersion())
+	})
+}
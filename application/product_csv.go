@@ -0,0 +1,255 @@
+package application
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	OUTCOME_UPDATED   = "updated"
+	OUTCOME_UNCHANGED = "unchanged"
+)
+
+var csvColumns = []string{"id", "name", "price", "status", "currency"}
+
+type ImportRow struct {
+	Line    int
+	Id      string
+	Outcome string
+	Err     error
+}
+
+type ImportReport struct {
+	DryRun bool
+	Rows   []ImportRow
+}
+
+func (r ImportReport) Failed() int {
+	failed := 0
+	for _, row := range r.Rows {
+		if row.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+type importedProduct struct {
+	product ProductInterface
+	changed bool
+}
+
+// Rows are all checked before anything is saved; a single failing row leaves
+// the catalog untouched. The currency column is optional and defaults to
+// DEFAULT_CURRENCY.
+func (s *ProductService) Import(ctx context.Context, r io.Reader, dryRun bool) (ImportReport, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return ImportReport{DryRun: dryRun}, nil
+	}
+	if err != nil {
+		return ImportReport{}, err
+	}
+	columns, err := csvHeader(header)
+	if err != nil {
+		return ImportReport{}, err
+	}
+
+	var report ImportReport
+	err = s.transaction(ctx, func(ctx context.Context, service *ProductService) error {
+		report = ImportReport{DryRun: dryRun}
+		var products []importedProduct
+		for {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			line, _ := reader.FieldPos(0)
+			product, outcome, err := importRecord(ctx, service.ProductPersistence, columns, record)
+			row := ImportRow{Line: line, Outcome: OUTCOME_FAILED, Err: err}
+			if product != nil {
+				row.Id = product.GetId()
+			}
+			if err == nil {
+				row.Outcome = outcome
+			}
+			report.Rows = append(report.Rows, row)
+			products = append(products, importedProduct{product: product, changed: outcome == OUTCOME_CREATED || outcome == OUTCOME_UPDATED})
+		}
+		if dryRun {
+			return errBatchRollback
+		}
+		if report.Failed() > 0 {
+			abortImport(report)
+			return errBatchRollback
+		}
+
+		for i, imported := range products {
+			if !imported.changed {
+				continue
+			}
+			if _, err := service.save(ctx, imported.product); err != nil {
+				report.Rows[i].Outcome = OUTCOME_FAILED
+				report.Rows[i].Err = err
+				abortImport(report)
+				return errBatchRollback
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchRollback) {
+		return ImportReport{}, err
+	}
+	return report, nil
+}
+
+func abortImport(report ImportReport) {
+	for i, row := range report.Rows {
+		if row.Err == nil {
+			report.Rows[i].Outcome = OUTCOME_FAILED
+			report.Rows[i].Err = ErrBatchAborted
+		}
+	}
+}
+
+func csvHeader(header []string) (map[string]int, error) {
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvColumns[:4] {
+		if _, ok := columns[name]; !ok {
+			return nil, NewValidationError(name, fmt.Sprintf("The CSV header must have the %s column", name), nil)
+		}
+	}
+	return columns, nil
+}
+
+func importRecord(ctx context.Context, persistence ProductPersistenceInterface, columns map[string]int, record []string) (ProductInterface, string, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	currency := field("currency")
+	if currency == "" {
+		currency = DEFAULT_CURRENCY
+	}
+	price, err := ParseMoney(field("price"), currency)
+	if err != nil {
+		return nil, "", err
+	}
+	name, status, id := field("name"), strings.ToLower(field("status")), field("id")
+
+	var product ProductInterface
+	outcome := OUTCOME_UPDATED
+	if id != "" {
+		product, err = persistence.Get(ctx, id)
+		// Archived products are hidden from Get but still own their id.
+		if errors.Is(err, ErrProductNotFound) {
+			product, err = persistence.GetArchived(ctx, id)
+		}
+	}
+	switch {
+	case id == "" || errors.Is(err, ErrProductNotFound):
+		if id == "" {
+			id = uuid.NewString()
+		}
+		product, outcome = newProduct(id, name, price), OUTCOME_CREATED
+	case err != nil:
+		return nil, "", err
+	}
+
+	before := stateOf(product)
+	if err := product.ChangeName(name); err != nil {
+		return product, "", err
+	}
+	if err := product.ChangePrice(price); err != nil {
+		return product, "", err
+	}
+	if status != "" && status != product.GetStatus() {
+		if err := changeStatus(product, status); err != nil {
+			return product, "", err
+		}
+	}
+	if err := checkEnabledPrice(product.GetStatus(), product.GetPrice()); err != nil {
+		return product, "", err
+	}
+	if valid, err := product.IsValid(); !valid {
+		return product, "", err
+	}
+	if outcome == OUTCOME_UPDATED && before == stateOf(product) {
+		outcome = OUTCOME_UNCHANGED
+	}
+	return product, outcome, nil
+}
+
+type productState struct {
+	name   string
+	price  Money
+	status string
+}
+
+func stateOf(product ProductInterface) productState {
+	return productState{name: product.GetName(), price: product.GetPrice(), status: product.GetStatus()}
+}
+
+func changeStatus(product ProductInterface, status string) error {
+	switch status {
+	case ENABLED:
+		return product.Enable()
+	case DISABLED:
+		return product.Disable()
+	case ARCHIVED:
+		return product.Archive()
+	default:
+		return NewValidationError("status", fmt.Sprintf("The status %q must be enabled, disabled or archived", status), nil)
+	}
+}
+
+// Products are read one page at a time and flushed after every page, so the
+// export never holds more than MAX_PAGE_SIZE products in memory.
+func (s *ProductService) Export(ctx context.Context, w io.Writer, query ProductQuery) error {
+	if query.Limit == 0 {
+		query.Limit = MAX_PAGE_SIZE
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvColumns); err != nil {
+		return err
+	}
+	for {
+		page, err := s.List(ctx, query)
+		if err != nil {
+			return err
+		}
+		for _, product := range page.Products {
+			price := product.GetPrice()
+			if err := writer.Write([]string{product.GetId(), product.GetName(), price.Decimal(), product.GetStatus(), price.Currency}); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
@@ -0,0 +1,177 @@
+package application_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductServiceImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	existingId := "a3f1c0de-8a4e-4c55-9d1c-7f7e2f1b8c01"
+	newId := "b6c2d1ef-9b5f-4d66-8e2d-8f8f3a2c9d12"
+	existingProduct := func() *application.Product {
+		product := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+		product.Id = existingId
+		product.ClearEvents()
+		return product
+	}
+
+	csv := "id,name,price,status\n" +
+		existingId + ",Product 1,12.50,enabled\n" +
+		newId + ",Product 2,3,disabled\n" +
+		",Product 3,0.99,enabled\n"
+
+	t.Run("Success - Create and update every row", func(t *testing.T) {
+		service, mockPersistence := newBatchService(ctrl)
+		existing := existingProduct()
+		mockPersistence.EXPECT().Get(gomock.Any(), existingId).Return(existing, nil)
+		mockPersistence.EXPECT().Get(gomock.Any(), newId).Return(nil, application.ErrProductNotFound)
+		mockPersistence.EXPECT().GetArchived(gomock.Any(), newId).Return(nil, application.ErrProductNotFound)
+		mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, product application.ProductInterface) (application.ProductInterface, error) {
+			for _, event := range product.Events() {
+				assert.Equal(t, product.GetId(), event.GetProductId())
+			}
+			return nil, nil
+		}).Times(3)
+
+		report, err := service.Import(context.Background(), strings.NewReader(csv), false)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, report.Failed())
+		assert.Equal(t, []int{2, 3, 4}, []int{report.Rows[0].Line, report.Rows[1].Line, report.Rows[2].Line})
+		assert.Equal(t, application.OUTCOME_UPDATED, report.Rows[0].Outcome)
+		assert.Equal(t, application.OUTCOME_CREATED, report.Rows[1].Outcome)
+		assert.Equal(t, newId, report.Rows[1].Id)
+		assert.Equal(t, application.OUTCOME_CREATED, report.Rows[2].Outcome)
+		assert.Equal(t, application.NewMoney(1250, application.DEFAULT_CURRENCY), existing.GetPrice())
+		assert.Equal(t, application.ENABLED, existing.GetStatus())
+	})
+
+	t.Run("Success - Dry run saves nothing", func(t *testing.T) {
+		service, mockPersistence := newBatchService(ctrl)
+		mockPersistence.EXPECT().Get(gomock.Any(), existingId).Return(existingProduct(), nil)
+		mockPersistence.EXPECT().Get(gomock.Any(), newId).Return(nil, application.ErrProductNotFound)
+		mockPersistence.EXPECT().GetArchived(gomock.Any(), newId).Return(nil, application.ErrProductNotFound)
+
+		report, err := service.Import(context.Background(), strings.NewReader(csv), true)
+		assert.Nil(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 0, report.Failed())
+		assert.Equal(t, application.OUTCOME_UPDATED, report.Rows[0].Outcome)
+	})
+
+	t.Run("Success - Unchanged rows are not saved", func(t *testing.T) {
+		service, mockPersistence := newBatchService(ctrl)
+		mockPersistence.EXPECT().Get(gomock.Any(), existingId).Return(existingProduct(), nil)
+
+		report, err := service.Import(context.Background(), strings.NewReader("id,name,price,status,currency\n"+existingId+",Product 1,10.00,disabled,brl\n"), false)
+		assert.Nil(t, err)
+		assert.Equal(t, application.OUTCOME_UNCHANGED, report.Rows[0].Outcome)
+	})
+
+	t.Run("Success - Archived products are matched by their id", func(t *testing.T) {
+		service, mockPersistence := newBatchService(ctrl)
+		archived := existingProduct()
+		assert.Nil(t, archived.Archive())
+		archived.ClearEvents()
+		mockPersistence.EXPECT().Get(gomock.Any(), existingId).Return(nil, application.ErrProductNotFound).Times(2)
+		mockPersistence.EXPECT().GetArchived(gomock.Any(), existingId).Return(archived, nil).Times(2)
+
+		report, err := service.Import(context.Background(), strings.NewReader("id,name,price,status\n"+existingId+",Product 1,10.00,archived\n"), false)
+		assert.Nil(t, err)
+		assert.Equal(t, application.OUTCOME_UNCHANGED, report.Rows[0].Outcome)
+
+		report, err = service.Import(context.Background(), strings.NewReader("id,name,price,status\n"+existingId+",Product 1,10.00,disabled\n"), false)
+		assert.Nil(t, err)
+		assert.ErrorIs(t, report.Rows[0].Err, application.ErrInvalidTransition)
+		assert.Equal(t, "The product must be restored before being disabled", report.Rows[0].Err.Error())
+	})
+
+	t.Run("Error - Report failing rows with their line numbers", func(t *testing.T) {
+		service, mockPersistence := newBatchService(ctrl)
+		mockPersistence.EXPECT().Get(gomock.Any(), "not-a-uuid").Return(nil, application.ErrProductNotFound)
+		mockPersistence.EXPECT().GetArchived(gomock.Any(), "not-a-uuid").Return(nil, application.ErrProductNotFound)
+		invalid := "id,name,price,status\n" +
+			",Product 1,10,enabled\n" +
+			",,10,disabled\n" +
+			",Product 3,ten,disabled\n" +
+			",Product 4,0,enabled\n" +
+			",Product 5,1,sold\n" +
+			"not-a-uuid,Product 6,1,disabled\n"
+
+		report, err := service.Import(context.Background(), strings.NewReader(invalid), false)
+		assert.Nil(t, err)
+		assert.Equal(t, 6, report.Failed())
+		assert.ErrorIs(t, report.Rows[0].Err, application.ErrBatchAborted)
+		assert.Equal(t, 3, report.Rows[1].Line)
+		assert.Equal(t, "The name must not be empty", report.Rows[1].Err.Error())
+		assert.ErrorIs(t, report.Rows[2].Err, application.ErrInvalidPrice)
+		assert.ErrorIs(t, report.Rows[3].Err, application.ErrInvalidTransition)
+		assert.Equal(t, `The status "sold" must be enabled, disabled or archived`, report.Rows[4].Err.Error())
+		var validationErr *application.ValidationError
+		assert.ErrorAs(t, report.Rows[5].Err, &validationErr)
+		assert.Equal(t, "id", validationErr.Fields[0].Field)
+	})
+
+	t.Run("Error - Enabled products keep a price", func(t *testing.T) {
+		service, mockPersistence := newBatchService(ctrl)
+		enabled := existingProduct()
+		assert.Nil(t, enabled.Enable())
+		enabled.ClearEvents()
+		mockPersistence.EXPECT().Get(gomock.Any(), existingId).Return(enabled, nil).Times(2)
+
+		for _, status := range []string{"enabled", ""} {
+			report, err := service.Import(context.Background(), strings.NewReader("id,name,price,status\n"+existingId+",Product 1,0,"+status+"\n"), false)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, report.Failed())
+			assert.ErrorIs(t, report.Rows[0].Err, application.ErrInvalidPrice)
+			assert.Equal(t, "The price must be greater than zero while the product is enabled", report.Rows[0].Err.Error())
+		}
+	})
+
+	t.Run("Error - Header without a required column", func(t *testing.T) {
+		service, _ := newBatchService(ctrl)
+
+		_, err := service.Import(context.Background(), strings.NewReader("id,name,status\n"), false)
+		assert.Equal(t, "The CSV header must have the price column", err.Error())
+	})
+}
+
+func TestProductServiceExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPersistence := mock.NewMockProductPersistenceInterface(ctrl)
+	service := application.NewProductService(mockPersistence)
+
+	first := application.NewProduct("Product, 1", application.NewMoney(1999, application.DEFAULT_CURRENCY))
+	second := application.NewProduct("Product 2", application.NewMoney(500, "JPY"))
+	cursor := application.NewProductCursor(first, application.SORT_BY_NAME).Encode()
+	gomock.InOrder(
+		mockPersistence.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, query application.ProductQuery) (application.ProductPage, error) {
+				assert.Equal(t, application.MAX_PAGE_SIZE, query.Limit)
+				assert.Empty(t, query.Cursor)
+				return application.ProductPage{Products: []application.ProductInterface{first}, NextCursor: cursor}, nil
+			}),
+		mockPersistence.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, query application.ProductQuery) (application.ProductPage, error) {
+				assert.Equal(t, cursor, query.Cursor)
+				return application.ProductPage{Products: []application.ProductInterface{second}}, nil
+			}),
+	)
+
+	var builder strings.Builder
+	err := service.Export(context.Background(), &builder, application.ProductQuery{})
+	assert.Nil(t, err)
+	assert.Equal(t, "id,name,price,status,currency\n"+
+		first.GetId()+",\"Product, 1\",19.99,disabled,BRL\n"+
+		second.GetId()+",Product 2,500,disabled,JPY\n", builder.String())
+}
@@ -0,0 +1,188 @@
+package application
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+const (
+	SORT_BY_NAME  = "name"
+	SORT_BY_PRICE = "price"
+
+	DEFAULT_PAGE_SIZE = 20
+	MAX_PAGE_SIZE     = 100
+)
+
+type ProductQuery struct {
+	Status   string
+	Name     string
+	MinPrice *Money
+	MaxPrice *Money
+	SortBy   string
+	Desc     bool
+	Limit    int
+	Cursor   string
+}
+
+type ProductPage struct {
+	Products   []ProductInterface
+	NextCursor string
+}
+
+type ProductCursor struct {
+	SortBy string `json:"s"`
+	Name   string `json:"n,omitempty"`
+	Price  int64  `json:"p,omitempty"`
+	Id     string `json:"i"`
+}
+
+func (q ProductQuery) Normalize() (ProductQuery, error) {
+	if q.Status != "" && q.Status != ENABLED && q.Status != DISABLED && q.Status != ARCHIVED {
+		return q, NewValidationError("status", "The status filter must be enabled, disabled or archived", nil)
+	}
+	if q.SortBy == "" {
+		q.SortBy = SORT_BY_NAME
+	}
+	if q.SortBy != SORT_BY_NAME && q.SortBy != SORT_BY_PRICE {
+		return q, NewValidationError("sort", "The products can only be sorted by name or price", nil)
+	}
+	for _, price := range []*Money{q.MinPrice, q.MaxPrice} {
+		if price == nil {
+			continue
+		}
+		if valid, err := price.IsValid(); !valid {
+			return q, err
+		}
+	}
+	if q.MinPrice != nil && q.MaxPrice != nil {
+		if q.MinPrice.Currency != q.MaxPrice.Currency {
+			return q, NewValidationError("price", "The price range must use a single currency", ErrInvalidPrice)
+		}
+		if q.MinPrice.Amount > q.MaxPrice.Amount {
+			return q, NewValidationError("price", "The minimum price must be less than or equal to the maximum price", ErrInvalidPrice)
+		}
+	}
+	if q.Limit < 0 {
+		return q, NewValidationError("limit", "The limit must be greater than or equal to zero", nil)
+	}
+	if q.Limit == 0 {
+		q.Limit = DEFAULT_PAGE_SIZE
+	}
+	if q.Limit > MAX_PAGE_SIZE {
+		q.Limit = MAX_PAGE_SIZE
+	}
+	if q.Cursor != "" {
+		cursor, err := DecodeProductCursor(q.Cursor)
+		if err != nil {
+			return q, err
+		}
+		if cursor.SortBy != q.SortBy {
+			return q, NewValidationError("cursor", "The cursor does not match the requested sorting", nil)
+		}
+	}
+	return q, nil
+}
+
+// Apply lists products held in memory with the same filters, ordering and
+// pagination the SQL adapter applies to its rows.
+func (q ProductQuery) Apply(products []ProductInterface) (ProductPage, error) {
+	var page ProductPage
+	q, err := q.Normalize()
+	if err != nil {
+		return page, err
+	}
+	var cursor *ProductCursor
+	if q.Cursor != "" {
+		decoded, err := DecodeProductCursor(q.Cursor)
+		if err != nil {
+			return page, err
+		}
+		cursor = &decoded
+	}
+
+	var matches []ProductInterface
+	for _, product := range products {
+		if q.matches(product) && (cursor == nil || q.compare(product, *cursor) > 0) {
+			matches = append(matches, product)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return q.compare(matches[i], NewProductCursor(matches[j], q.SortBy)) < 0
+	})
+
+	if len(matches) > q.Limit {
+		matches = matches[:q.Limit]
+		page.NextCursor = NewProductCursor(matches[q.Limit-1], q.SortBy).Encode()
+	}
+	page.Products = matches
+	return page, nil
+}
+
+func (q ProductQuery) matches(product ProductInterface) bool {
+	if q.Status == "" && product.GetDeletedAt() != nil {
+		return false
+	}
+	if q.Status != "" && product.GetStatus() != q.Status {
+		return false
+	}
+	if q.Name != "" && !strings.Contains(strings.ToLower(product.GetName()), strings.ToLower(q.Name)) {
+		return false
+	}
+	price := product.GetPrice()
+	if q.MinPrice != nil && (price.Currency != q.MinPrice.Currency || price.Amount < q.MinPrice.Amount) {
+		return false
+	}
+	if q.MaxPrice != nil && (price.Currency != q.MaxPrice.Currency || price.Amount > q.MaxPrice.Amount) {
+		return false
+	}
+	return true
+}
+
+// compare orders a product against a cursor position in the requested
+// direction, breaking ties by id.
+func (q ProductQuery) compare(product ProductInterface, cursor ProductCursor) int {
+	var result int
+	if q.SortBy == SORT_BY_PRICE {
+		result = cmp.Compare(product.GetPrice().Amount, cursor.Price)
+	} else {
+		result = strings.Compare(product.GetName(), cursor.Name)
+	}
+	if result == 0 {
+		result = strings.Compare(product.GetId(), cursor.Id)
+	}
+	if q.Desc {
+		return -result
+	}
+	return result
+}
+
+func NewProductCursor(product ProductInterface, sortBy string) ProductCursor {
+	cursor := ProductCursor{SortBy: sortBy, Id: product.GetId()}
+	switch sortBy {
+	case SORT_BY_PRICE:
+		cursor.Price = product.GetPrice().Amount
+	default:
+		cursor.Name = product.GetName()
+	}
+	return cursor
+}
+
+func (c ProductCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeProductCursor(cursor string) (ProductCursor, error) {
+	var result ProductCursor
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return result, NewValidationError("cursor", "The cursor is invalid", nil)
+	}
+	if err := json.Unmarshal(data, &result); err != nil || result.Id == "" {
+		return result, NewValidationError("cursor", "The cursor is invalid", nil)
+	}
+	return result, nil
+}
@@ -0,0 +1,142 @@
+package application_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductQueryNormalize(t *testing.T) {
+	minPrice := application.NewMoney(2000, application.DEFAULT_CURRENCY)
+	maxPrice := application.NewMoney(1000, application.DEFAULT_CURRENCY)
+	otherPrice := application.NewMoney(3000, "USD")
+	nameCursor := application.ProductCursor{SortBy: application.SORT_BY_NAME, Name: "Product 1", Id: "1"}.Encode()
+
+	tests := []struct {
+		name     string
+		query    application.ProductQuery
+		expected application.ProductQuery
+		err      string
+	}{
+		{
+			name:     "Defaults",
+			query:    application.ProductQuery{},
+			expected: application.ProductQuery{SortBy: application.SORT_BY_NAME, Limit: application.DEFAULT_PAGE_SIZE},
+		},
+		{
+			name:     "Limit above the maximum page size",
+			query:    application.ProductQuery{SortBy: application.SORT_BY_PRICE, Limit: 1000},
+			expected: application.ProductQuery{SortBy: application.SORT_BY_PRICE, Limit: application.MAX_PAGE_SIZE},
+		},
+		{
+			name:  "Invalid status",
+			query: application.ProductQuery{Status: "invalid"},
+			err:   "The status filter must be enabled, disabled or archived",
+		},
+		{
+			name:  "Invalid sorting",
+			query: application.ProductQuery{SortBy: "status"},
+			err:   "The products can only be sorted by name or price",
+		},
+		{
+			name:  "Invalid price range",
+			query: application.ProductQuery{MinPrice: &minPrice, MaxPrice: &maxPrice},
+			err:   "The minimum price must be less than or equal to the maximum price",
+		},
+		{
+			name:  "Price range with different currencies",
+			query: application.ProductQuery{MinPrice: &minPrice, MaxPrice: &otherPrice},
+			err:   "The price range must use a single currency",
+		},
+		{
+			name:  "Negative limit",
+			query: application.ProductQuery{Limit: -1},
+			err:   "The limit must be greater than or equal to zero",
+		},
+		{
+			name:  "Invalid cursor",
+			query: application.ProductQuery{Cursor: "invalid"},
+			err:   "The cursor is invalid",
+		},
+		{
+			name:  "Cursor from another sorting",
+			query: application.ProductQuery{SortBy: application.SORT_BY_PRICE, Cursor: nameCursor},
+			err:   "The cursor does not match the requested sorting",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.query.Normalize()
+			if tt.err != "" {
+				assert.Equal(t, tt.err, err.Error())
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestProductCursor(t *testing.T) {
+	product := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+
+	cursor := application.NewProductCursor(product, application.SORT_BY_PRICE)
+	result, err := application.DecodeProductCursor(cursor.Encode())
+
+	assert.Nil(t, err)
+	assert.Equal(t, application.SORT_BY_PRICE, result.SortBy)
+	assert.Equal(t, int64(1000), result.Price)
+	assert.Equal(t, product.GetId(), result.Id)
+}
+
+func TestProductQueryApply(t *testing.T) {
+	first := &application.Product{Id: "1", Name: "Apple", Price: application.NewMoney(300, application.DEFAULT_CURRENCY), Status: application.ENABLED}
+	second := &application.Product{Id: "2", Name: "banana", Price: application.NewMoney(100, application.DEFAULT_CURRENCY), Status: application.DISABLED}
+	third := &application.Product{Id: "3", Name: "Cherry", Price: application.NewMoney(100, application.DEFAULT_CURRENCY), Status: application.ENABLED}
+	deletedAt := time.Now()
+	archived := &application.Product{Id: "4", Name: "Apricot", Price: application.NewMoney(200, application.DEFAULT_CURRENCY), Status: application.ARCHIVED, DeletedAt: &deletedAt}
+	products := []application.ProductInterface{third, archived, second, first}
+
+	t.Run("Success - Hide archived products and sort by name", func(t *testing.T) {
+		page, err := application.ProductQuery{}.Apply(products)
+		assert.Nil(t, err)
+		assert.Equal(t, []application.ProductInterface{first, third, second}, page.Products)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("Success - Filter by status, name and price", func(t *testing.T) {
+		page, err := application.ProductQuery{Status: application.ARCHIVED}.Apply(products)
+		assert.Nil(t, err)
+		assert.Equal(t, []application.ProductInterface{archived}, page.Products)
+
+		page, err = application.ProductQuery{Name: "AN"}.Apply(products)
+		assert.Nil(t, err)
+		assert.Equal(t, []application.ProductInterface{second}, page.Products)
+
+		maxPrice := application.NewMoney(100, application.DEFAULT_CURRENCY)
+		page, err = application.ProductQuery{MaxPrice: &maxPrice}.Apply(products)
+		assert.Nil(t, err)
+		assert.Equal(t, []application.ProductInterface{third, second}, page.Products)
+	})
+
+	t.Run("Success - Page by price in descending order", func(t *testing.T) {
+		query := application.ProductQuery{SortBy: application.SORT_BY_PRICE, Desc: true, Limit: 2}
+		page, err := query.Apply(products)
+		assert.Nil(t, err)
+		assert.Equal(t, []application.ProductInterface{first, third}, page.Products)
+
+		query.Cursor = page.NextCursor
+		page, err = query.Apply(products)
+		assert.Nil(t, err)
+		assert.Equal(t, []application.ProductInterface{second}, page.Products)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("Error - Apply an invalid query", func(t *testing.T) {
+		_, err := application.ProductQuery{Status: "sold"}.Apply(products)
+		assert.NotNil(t, err)
+	})
+}
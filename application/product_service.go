@@ -1,51 +1,208 @@
 package application
 
+import "context"
+
 type ProductService struct {
 	ProductPersistence ProductPersistenceInterface
+	EventPublisher     EventPublisherInterface
+	// rollback gives saved products their events back when the transaction
+	// the service is bound to does not commit.
+	rollback *[]func()
 }
 
 func NewProductService(p ProductPersistenceInterface) *ProductService {
 	return &ProductService{ProductPersistence: p}
 }
 
-func (s *ProductService) Get(id string) (ProductInterface, error) {
-	product, err := s.ProductPersistence.Get(id)
+func (s *ProductService) Get(ctx context.Context, id string) (ProductInterface, error) {
+	product, err := s.ProductPersistence.Get(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	return product, nil
 }
 
-func (s *ProductService) Create(name string, price float64) (ProductInterface, error) {
+func (s *ProductService) List(ctx context.Context, query ProductQuery) (ProductPage, error) {
+	query, err := query.Normalize()
+	if err != nil {
+		return ProductPage{}, err
+	}
+	page, err := s.ProductPersistence.List(ctx, query)
+	if err != nil {
+		return ProductPage{}, err
+	}
+	return page, nil
+}
+
+func (s *ProductService) Create(ctx context.Context, name string, price Money) (ProductInterface, error) {
 	product := NewProduct(name, price)
 	if valid, err := product.IsValid(); !valid {
 		return nil, err
 	}
-	result, err := s.ProductPersistence.Save(product)
+	result, err := s.save(ctx, product)
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (s *ProductService) Enable(product ProductInterface) (ProductInterface, error) {
+func (s *ProductService) Enable(ctx context.Context, product ProductInterface) (ProductInterface, error) {
 	if err := product.Enable(); err != nil {
 		return nil, err
 	}
-	result, err := s.ProductPersistence.Save(product)
+	result, err := s.save(ctx, product)
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (s *ProductService) Disable(product ProductInterface) (ProductInterface, error) {
+func (s *ProductService) Disable(ctx context.Context, product ProductInterface) (ProductInterface, error) {
 	if err := product.Disable(); err != nil {
 		return nil, err
 	}
-	result, err := s.ProductPersistence.Save(product)
+	result, err := s.save(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *ProductService) ChangeName(ctx context.Context, product ProductInterface, name string) (ProductInterface, error) {
+	if err := product.ChangeName(name); err != nil {
+		return nil, err
+	}
+	if valid, err := product.IsValid(); !valid {
+		return nil, err
+	}
+	result, err := s.save(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *ProductService) ChangePrice(ctx context.Context, product ProductInterface, price Money) (ProductInterface, error) {
+	if err := checkEnabledPrice(product.GetStatus(), price); err != nil {
+		return nil, err
+	}
+	if err := product.ChangePrice(price); err != nil {
+		return nil, err
+	}
+	if valid, err := product.IsValid(); !valid {
+		return nil, err
+	}
+	result, err := s.save(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *ProductService) Archive(ctx context.Context, product ProductInterface) (ProductInterface, error) {
+	if err := product.Archive(); err != nil {
+		return nil, err
+	}
+	result, err := s.save(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *ProductService) Restore(ctx context.Context, id string) (ProductInterface, error) {
+	product, err := s.ProductPersistence.GetArchived(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := product.Restore(); err != nil {
+		return nil, err
+	}
+	result, err := s.save(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *ProductService) Purge(ctx context.Context, id string) error {
+	if err := s.ProductPersistence.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.dispatch(ctx, []DomainEvent{NewProductPurged(id)})
+	return nil
+}
+
+func (s *ProductService) WithinTransaction(ctx context.Context, fn func(ctx context.Context, service ProductServiceInterface) error) error {
+	return s.transaction(ctx, func(ctx context.Context, service *ProductService) error {
+		return fn(ctx, service)
+	})
+}
+
+// fn receives a service bound to the transaction whose events are held back
+// until the transaction commits. Persistence adapters without transaction
+// support run fn directly, so its writes are applied one by one.
+func (s *ProductService) transaction(ctx context.Context, fn func(ctx context.Context, service *ProductService) error) error {
+	buffer := &eventBuffer{}
+	transaction, ok := s.ProductPersistence.(ProductTransactionInterface)
+	if !ok {
+		err := fn(ctx, &ProductService{ProductPersistence: s.ProductPersistence, EventPublisher: buffer})
+		if err != nil {
+			return err
+		}
+		s.dispatch(ctx, buffer.events)
+		return nil
+	}
+
+	var rollback []func()
+	err := transaction.WithinTransaction(ctx, func(ctx context.Context, persistence ProductPersistenceInterface) error {
+		return fn(ctx, &ProductService{ProductPersistence: persistence, EventPublisher: buffer, rollback: &rollback})
+	})
+	if err != nil {
+		for i := len(rollback) - 1; i >= 0; i-- {
+			rollback[i]()
+		}
+		return err
+	}
+	// A nested transaction is only committed with the one it joined.
+	if s.rollback != nil {
+		*s.rollback = append(*s.rollback, rollback...)
+	}
+
+	s.dispatch(ctx, buffer.events)
+	return nil
+}
+
+// Enabled products must keep a price, which Product.ChangePrice alone does
+// not check.
+func checkEnabledPrice(status string, price Money) error {
+	if status == ENABLED && !price.IsPositive() {
+		return NewValidationError("price", "The price must be greater than zero while the product is enabled", ErrInvalidPrice)
+	}
+	return nil
+}
+
+func (s *ProductService) save(ctx context.Context, product ProductInterface) (ProductInterface, error) {
+	result, err := s.ProductPersistence.Save(ctx, product)
 	if err != nil {
 		return nil, err
 	}
+	events := product.Events()
+	product.ClearEvents()
+	if restorer, ok := product.(eventRestorer); ok && s.rollback != nil {
+		*s.rollback = append(*s.rollback, func() {
+			restorer.restoreEvents(events)
+		})
+	}
+	s.dispatch(ctx, events)
 	return result, nil
 }
+
+// The change is already saved when its events are dispatched, so a failing
+// subscriber cannot undo it; the publisher is responsible for reporting it.
+func (s *ProductService) dispatch(ctx context.Context, events []DomainEvent) {
+	if s.EventPublisher == nil || len(events) == 0 {
+		return
+	}
+	_ = s.EventPublisher.Publish(ctx, events...)
+}
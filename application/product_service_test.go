@@ -1,6 +1,7 @@
 package application_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -19,20 +20,20 @@ func TestProductServiceGet(t *testing.T) {
 		ProductPersistence: mockPersistence,
 	}
 
-	product := application.NewProduct("Product 1", 10)
+	product := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
 
 	t.Run("Product exists", func(t *testing.T) {
-		mockPersistence.EXPECT().Get(gomock.Any()).Return(product, nil).Times(1)
+		mockPersistence.EXPECT().Get(gomock.Any(), gomock.Any()).Return(product, nil).Times(1)
 
-		result, err := service.Get("abc")
+		result, err := service.Get(context.Background(), "abc")
 		assert.Nil(t, err)
 		assert.Equal(t, product, result)
 	})
 
 	t.Run("Product persistence throw error", func(t *testing.T) {
-		mockPersistence.EXPECT().Get(gomock.Any()).Return(nil, errors.New("Internal error")).Times(1)
+		mockPersistence.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, errors.New("Internal error")).Times(1)
 
-		result, err := service.Get("abc")
+		result, err := service.Get(context.Background(), "abc")
 		assert.Nil(t, result)
 		assert.Equal(t, "Internal error", err.Error())
 	})
@@ -48,26 +49,26 @@ func TestProductServiceCreate(t *testing.T) {
 	}
 
 	t.Run("Success", func(t *testing.T) {
-		product := application.NewProduct("Product 2", 10)
+		product := application.NewProduct("Product 2", application.NewMoney(1000, application.DEFAULT_CURRENCY))
 
-		mockPersistence.EXPECT().Save(gomock.Any()).Return(product, nil).Times(1)
+		mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).Return(product, nil).Times(1)
 
-		result, err := service.Create("Product 2", 10)
+		result, err := service.Create(context.Background(), "Product 2", application.NewMoney(1000, application.DEFAULT_CURRENCY))
 		assert.Nil(t, err)
 		assert.Equal(t, result, product)
 	})
 
 	t.Run("Error - Create product with negative price", func(t *testing.T) {
-		result, err := service.Create("Product with error", -10)
+		result, err := service.Create(context.Background(), "Product with error", application.NewMoney(-1000, application.DEFAULT_CURRENCY))
 		assert.Nil(t, result)
 		assert.NotNil(t, err)
 		assert.Equal(t, "The price must be greater than or equal to zero", err.Error())
 	})
 
 	t.Run("Error - Save persistence throws an error", func(t *testing.T) {
-		mockPersistence.EXPECT().Save(gomock.Any()).Return(nil, errors.New("Internal error")).Times(1)
+		mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil, errors.New("Internal error")).Times(1)
 
-		result, err := service.Create("Product a", 10)
+		result, err := service.Create(context.Background(), "Product a", application.NewMoney(1000, application.DEFAULT_CURRENCY))
 		assert.Nil(t, result)
 		assert.Equal(t, "Internal error", err.Error())
 	})
@@ -83,30 +84,40 @@ func TestProductServiceEnable(t *testing.T) {
 	}
 
 	t.Run("Success", func(t *testing.T) {
-		product := application.NewProduct("Product 3", 10)
+		product := application.NewProduct("Product 3", application.NewMoney(1000, application.DEFAULT_CURRENCY))
 
-		mockPersistence.EXPECT().Save(gomock.Any()).Return(product, nil).Times(1)
+		mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).Return(product, nil).Times(1)
 
-		result, err := service.Enable(product)
+		result, err := service.Enable(context.Background(), product)
 		assert.Nil(t, err)
 		assert.Equal(t, result, product)
 	})
 
 	t.Run("Error - Enable product without price", func(t *testing.T) {
-		product := application.NewProduct("Product 3", 0)
+		product := application.NewProduct("Product 3", application.NewMoney(0, application.DEFAULT_CURRENCY))
 
-		result, err := service.Enable(product)
+		result, err := service.Enable(context.Background(), product)
 		assert.Nil(t, result)
 		assert.NotNil(t, err)
 		assert.Equal(t, "The price must be greater than zero to enable the product", err.Error())
 	})
 
+	t.Run("Error - Save reports a concurrent modification", func(t *testing.T) {
+		product := application.NewProduct("Product 3", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+
+		mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil, application.ErrConcurrentModification).Times(1)
+
+		result, err := service.Enable(context.Background(), product)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, application.ErrConcurrentModification)
+	})
+
 	t.Run("Error - Save persistence throws an error", func(t *testing.T) {
-		product := application.NewProduct("Product 3", 10)
+		product := application.NewProduct("Product 3", application.NewMoney(1000, application.DEFAULT_CURRENCY))
 
-		mockPersistence.EXPECT().Save(gomock.Any()).Return(nil, errors.New("Internal error")).Times(1)
+		mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil, errors.New("Internal error")).Times(1)
 
-		result, err := service.Enable(product)
+		result, err := service.Enable(context.Background(), product)
 		assert.Nil(t, result)
 		assert.Equal(t, "Internal error", err.Error())
 	})
@@ -122,45 +133,473 @@ func TestProductServiceDisable(t *testing.T) {
 	}
 
 	t.Run("Success", func(t *testing.T) {
-		product := application.NewProduct("Product 4", 10)
+		product := application.NewProduct("Product 4", application.NewMoney(1000, application.DEFAULT_CURRENCY))
 		product.Enable()
-		product.ChangePrice(0)
+		product.ChangePrice(application.NewMoney(0, application.DEFAULT_CURRENCY))
 
-		mockPersistence.EXPECT().Save(gomock.Any()).Return(product, nil).Times(1)
+		mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).Return(product, nil).Times(1)
 
-		result, err := service.Disable(product)
+		result, err := service.Disable(context.Background(), product)
 		assert.Nil(t, err)
 		assert.Equal(t, result, product)
 	})
 
 	t.Run("Success - Disable already disabled product", func(t *testing.T) {
-		product := application.NewProduct("Product 4", 0)
+		product := application.NewProduct("Product 4", application.NewMoney(0, application.DEFAULT_CURRENCY))
 
-		mockPersistence.EXPECT().Save(gomock.Any()).Return(product, nil).Times(1)
+		mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).Return(product, nil).Times(1)
 
-		result, err := service.Disable(product)
+		result, err := service.Disable(context.Background(), product)
 		assert.Nil(t, err)
 		assert.Equal(t, result, product)
 	})
 
 	t.Run("Error - Disable product with price greater than zero", func(t *testing.T) {
-		product := application.NewProduct("Product 4", 10)
+		product := application.NewProduct("Product 4", application.NewMoney(1000, application.DEFAULT_CURRENCY))
 
-		result, err := service.Disable(product)
+		result, err := service.Disable(context.Background(), product)
 		assert.Nil(t, result)
 		assert.NotNil(t, err)
 		assert.Equal(t, "The price must be zero to disable the product", err.Error())
 	})
 
 	t.Run("Error - Save persistence throws an error", func(t *testing.T) {
-		product := application.NewProduct("Product 4", 10)
+		product := application.NewProduct("Product 4", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+		product.Enable()
+		product.ChangePrice(application.NewMoney(0, application.DEFAULT_CURRENCY))
+
+		mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil, errors.New("Internal error")).Times(1)
+
+		result, err := service.Disable(context.Background(), product)
+		assert.Nil(t, result)
+		assert.Equal(t, "Internal error", err.Error())
+	})
+}
+
+func TestProductServiceList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPersistence := mock.NewMockProductPersistenceInterface(ctrl)
+	service := application.ProductService{
+		ProductPersistence: mockPersistence,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		page := application.ProductPage{
+			Products: []application.ProductInterface{application.NewProduct("Product 5", application.NewMoney(1000, application.DEFAULT_CURRENCY))},
+		}
+		expectedQuery := application.ProductQuery{
+			Status: application.ENABLED,
+			SortBy: application.SORT_BY_NAME,
+			Limit:  application.DEFAULT_PAGE_SIZE,
+		}
+
+		mockPersistence.EXPECT().List(gomock.Any(), expectedQuery).Return(page, nil).Times(1)
+
+		result, err := service.List(context.Background(), application.ProductQuery{Status: application.ENABLED})
+		assert.Nil(t, err)
+		assert.Equal(t, page, result)
+	})
+
+	t.Run("Error - List with an invalid query", func(t *testing.T) {
+		result, err := service.List(context.Background(), application.ProductQuery{SortBy: "status"})
+		assert.Empty(t, result.Products)
+		assert.Equal(t, "The products can only be sorted by name or price", err.Error())
+	})
+
+	t.Run("Error - List persistence throws an error", func(t *testing.T) {
+		mockPersistence.EXPECT().List(gomock.Any(), gomock.Any()).Return(application.ProductPage{}, errors.New("Internal error")).Times(1)
+
+		result, err := service.List(context.Background(), application.ProductQuery{})
+		assert.Empty(t, result.Products)
+		assert.Equal(t, "Internal error", err.Error())
+	})
+}
+
+func TestProductServiceChangeName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPersistence := mock.NewMockProductPersistenceInterface(ctrl)
+	service := application.ProductService{
+		ProductPersistence: mockPersistence,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		product := application.NewProduct("Product 6", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+
+		mockPersistence.EXPECT().Save(gomock.Any(), product).Return(product, nil).Times(1)
+
+		result, err := service.ChangeName(context.Background(), product, "Product 7")
+		assert.Nil(t, err)
+		assert.Equal(t, "Product 7", result.GetName())
+	})
+
+	t.Run("Error - Rename to an empty name", func(t *testing.T) {
+		product := application.NewProduct("Product 6", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+
+		result, err := service.ChangeName(context.Background(), product, "")
+		assert.Nil(t, result)
+		assert.Equal(t, "The name must not be empty", err.Error())
+	})
+
+	t.Run("Error - Save persistence throws an error", func(t *testing.T) {
+		product := application.NewProduct("Product 6", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+
+		mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil, errors.New("Internal error")).Times(1)
+
+		result, err := service.ChangeName(context.Background(), product, "Product 7")
+		assert.Nil(t, result)
+		assert.Equal(t, "Internal error", err.Error())
+	})
+}
+
+func TestProductServiceChangePrice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPersistence := mock.NewMockProductPersistenceInterface(ctrl)
+	service := application.ProductService{
+		ProductPersistence: mockPersistence,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		product := application.NewProduct("Product 7", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+		product.Enable()
+
+		mockPersistence.EXPECT().Save(gomock.Any(), product).Return(product, nil).Times(1)
+
+		result, err := service.ChangePrice(context.Background(), product, application.NewMoney(1999, application.DEFAULT_CURRENCY))
+		assert.Nil(t, err)
+		assert.Equal(t, application.NewMoney(1999, application.DEFAULT_CURRENCY), result.GetPrice())
+	})
+
+	t.Run("Success - Change a disabled product price to zero", func(t *testing.T) {
+		product := application.NewProduct("Product 7", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+
+		mockPersistence.EXPECT().Save(gomock.Any(), product).Return(product, nil).Times(1)
+
+		result, err := service.ChangePrice(context.Background(), product, application.NewMoney(0, application.DEFAULT_CURRENCY))
+		assert.Nil(t, err)
+		assert.True(t, result.GetPrice().IsZero())
+	})
+
+	t.Run("Error - Change an enabled product price to zero", func(t *testing.T) {
+		product := application.NewProduct("Product 7", application.NewMoney(1000, application.DEFAULT_CURRENCY))
 		product.Enable()
-		product.ChangePrice(0)
 
-		mockPersistence.EXPECT().Save(gomock.Any()).Return(nil, errors.New("Internal error")).Times(1)
+		result, err := service.ChangePrice(context.Background(), product, application.NewMoney(0, application.DEFAULT_CURRENCY))
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, application.ErrInvalidPrice)
+		assert.Equal(t, "The price must be greater than zero while the product is enabled", err.Error())
+		assert.Equal(t, application.NewMoney(1000, application.DEFAULT_CURRENCY), product.GetPrice())
+	})
+
+	t.Run("Error - Change the price to a negative value", func(t *testing.T) {
+		product := application.NewProduct("Product 7", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+
+		result, err := service.ChangePrice(context.Background(), product, application.NewMoney(-1, application.DEFAULT_CURRENCY))
+		assert.Nil(t, result)
+		assert.Equal(t, "The price must be greater than or equal to zero", err.Error())
+	})
+
+	t.Run("Error - Save persistence throws an error", func(t *testing.T) {
+		product := application.NewProduct("Product 7", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+
+		mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil, errors.New("Internal error")).Times(1)
 
-		result, err := service.Disable(product)
+		result, err := service.ChangePrice(context.Background(), product, application.NewMoney(2000, application.DEFAULT_CURRENCY))
 		assert.Nil(t, result)
 		assert.Equal(t, "Internal error", err.Error())
 	})
 }
+
+func TestProductServiceArchive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPersistence := mock.NewMockProductPersistenceInterface(ctrl)
+	service := application.ProductService{
+		ProductPersistence: mockPersistence,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		product := application.NewProduct("Product 8", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+
+		mockPersistence.EXPECT().Save(gomock.Any(), product).Return(product, nil).Times(1)
+
+		result, err := service.Archive(context.Background(), product)
+		assert.Nil(t, err)
+		assert.Equal(t, application.ARCHIVED, result.GetStatus())
+	})
+
+	t.Run("Error - Archive an archived product", func(t *testing.T) {
+		product := application.NewProduct("Product 8", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+		product.Archive()
+
+		result, err := service.Archive(context.Background(), product)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, application.ErrInvalidTransition)
+	})
+}
+
+func TestProductServiceRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPersistence := mock.NewMockProductPersistenceInterface(ctrl)
+	service := application.ProductService{
+		ProductPersistence: mockPersistence,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		product := application.NewProduct("Product 9", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+		product.Archive()
+
+		mockPersistence.EXPECT().GetArchived(gomock.Any(), product.GetId()).Return(product, nil).Times(1)
+		mockPersistence.EXPECT().Save(gomock.Any(), product).Return(product, nil).Times(1)
+
+		result, err := service.Restore(context.Background(), product.GetId())
+		assert.Nil(t, err)
+		assert.Equal(t, application.DISABLED, result.GetStatus())
+	})
+
+	t.Run("Error - Restore a product that is not archived", func(t *testing.T) {
+		mockPersistence.EXPECT().GetArchived(gomock.Any(), "abc").Return(nil, application.ErrProductNotFound).Times(1)
+
+		result, err := service.Restore(context.Background(), "abc")
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, application.ErrProductNotFound)
+	})
+}
+
+func TestProductServicePurge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPersistence := mock.NewMockProductPersistenceInterface(ctrl)
+	publisher := &recordingPublisher{}
+	service := application.ProductService{
+		ProductPersistence: mockPersistence,
+		EventPublisher:     publisher,
+	}
+
+	mockPersistence.EXPECT().Delete(gomock.Any(), "abc").Return(nil).Times(1)
+	assert.Nil(t, service.Purge(context.Background(), "abc"))
+	if assert.Len(t, publisher.events, 1) {
+		assert.Equal(t, application.PRODUCT_PURGED, publisher.events[0].EventName())
+		assert.Equal(t, "abc", publisher.events[0].GetProductId())
+	}
+
+	mockPersistence.EXPECT().Delete(gomock.Any(), "abc").Return(application.ErrProductNotFound).Times(1)
+	assert.ErrorIs(t, service.Purge(context.Background(), "abc"), application.ErrProductNotFound)
+	assert.Len(t, publisher.events, 1)
+}
+
+func TestProductServiceHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPersistence := mock.NewMockProductPersistenceInterface(ctrl)
+	service := application.ProductService{
+		ProductPersistence: mockPersistence,
+	}
+
+	changes := []application.ProductChange{{ProductId: "abc", Version: 1, Field: "name", NewValue: "Product", Actor: "alice"}}
+	mockPersistence.EXPECT().History(gomock.Any(), "abc").Return(changes, nil).Times(1)
+	result, err := service.History(context.Background(), "abc")
+	assert.Nil(t, err)
+	assert.Equal(t, changes, result)
+
+	mockPersistence.EXPECT().History(gomock.Any(), "abc").Return(nil, application.ErrProductNotFound).Times(1)
+	_, err = service.History(context.Background(), "abc")
+	assert.ErrorIs(t, err, application.ErrProductNotFound)
+}
+
+func TestActor(t *testing.T) {
+	assert.Equal(t, application.UNKNOWN_ACTOR, application.ActorFromContext(context.Background()))
+	assert.Equal(t, "alice", application.ActorFromContext(application.WithActor(context.Background(), "alice")))
+	assert.Equal(t, application.UNKNOWN_ACTOR, application.ActorFromContext(application.WithActor(context.Background(), "")))
+}
+
+type transactionalPersistence struct {
+	*mock.MockProductPersistenceInterface
+	*mock.MockProductTransactionInterface
+}
+
+func TestProductServiceWithinTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	product := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+
+	t.Run("Success - Run every operation in one transaction", func(t *testing.T) {
+		mockPersistence := mock.NewMockProductPersistenceInterface(ctrl)
+		mockTransaction := mock.NewMockProductTransactionInterface(ctrl)
+		txPersistence := mock.NewMockProductPersistenceInterface(ctrl)
+		service := application.NewProductService(transactionalPersistence{mockPersistence, mockTransaction})
+
+		mockTransaction.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context, application.ProductPersistenceInterface) error) error {
+				return fn(ctx, txPersistence)
+			}).Times(1)
+		txPersistence.EXPECT().Save(gomock.Any(), product).Return(product, nil).Times(2)
+
+		err := service.WithinTransaction(context.Background(), func(ctx context.Context, service application.ProductServiceInterface) error {
+			if _, err := service.Enable(ctx, product); err != nil {
+				return err
+			}
+			_, err := service.ChangeName(ctx, product, "Product 2")
+			return err
+		})
+		assert.Nil(t, err)
+	})
+
+	t.Run("Error - Return the error that aborted the transaction", func(t *testing.T) {
+		mockPersistence := mock.NewMockProductPersistenceInterface(ctrl)
+		mockTransaction := mock.NewMockProductTransactionInterface(ctrl)
+		service := application.NewProductService(transactionalPersistence{mockPersistence, mockTransaction})
+
+		mockTransaction.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context, application.ProductPersistenceInterface) error) error {
+				return fn(ctx, mockPersistence)
+			}).Times(1)
+		mockPersistence.EXPECT().Delete(gomock.Any(), "abc").Return(application.ErrProductNotFound).Times(1)
+
+		err := service.WithinTransaction(context.Background(), func(ctx context.Context, service application.ProductServiceInterface) error {
+			return service.Purge(ctx, "abc")
+		})
+		assert.ErrorIs(t, err, application.ErrProductNotFound)
+	})
+
+	t.Run("Success - Run directly without transaction support", func(t *testing.T) {
+		mockPersistence := mock.NewMockProductPersistenceInterface(ctrl)
+		service := application.NewProductService(mockPersistence)
+
+		mockPersistence.EXPECT().Delete(gomock.Any(), "abc").Return(nil).Times(1)
+
+		err := service.WithinTransaction(context.Background(), func(ctx context.Context, service application.ProductServiceInterface) error {
+			return service.Purge(ctx, "abc")
+		})
+		assert.Nil(t, err)
+	})
+}
+
+type recordingPublisher struct {
+	events []application.DomainEvent
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, events ...application.DomainEvent) error {
+	p.events = append(p.events, events...)
+	return errors.New("subscriber failed")
+}
+
+func TestProductServiceEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Success - Dispatch the events after saving", func(t *testing.T) {
+		mockPersistence := mock.NewMockProductPersistenceInterface(ctrl)
+		publisher := &recordingPublisher{}
+		service := application.ProductService{ProductPersistence: mockPersistence, EventPublisher: publisher}
+
+		saves := 0
+		mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, product application.ProductInterface) (application.ProductInterface, error) {
+				assert.Len(t, publisher.events, saves)
+				saves++
+				return product, nil
+			}).Times(2)
+
+		product, err := service.Create(context.Background(), "Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+		assert.Nil(t, err)
+		_, err = service.Enable(context.Background(), product)
+		assert.Nil(t, err)
+
+		assert.Len(t, publisher.events, 2)
+		assert.Equal(t, application.PRODUCT_CREATED, publisher.events[0].EventName())
+		assert.Equal(t, application.PRODUCT_ENABLED, publisher.events[1].EventName())
+		assert.Empty(t, product.Events())
+	})
+
+	t.Run("Error - Keep the events when saving fails", func(t *testing.T) {
+		mockPersistence := mock.NewMockProductPersistenceInterface(ctrl)
+		publisher := &recordingPublisher{}
+		service := application.ProductService{ProductPersistence: mockPersistence, EventPublisher: publisher}
+		product := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+
+		mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil, application.ErrConcurrentModification)
+
+		_, err := service.Enable(context.Background(), product)
+		assert.ErrorIs(t, err, application.ErrConcurrentModification)
+		assert.Empty(t, publisher.events)
+		assert.Len(t, product.Events(), 2)
+	})
+
+	t.Run("Success - Hold the events until the transaction commits", func(t *testing.T) {
+		mockPersistence := mock.NewMockProductPersistenceInterface(ctrl)
+		mockTransaction := mock.NewMockProductTransactionInterface(ctrl)
+		publisher := &recordingPublisher{}
+		service := application.ProductService{ProductPersistence: transactionalPersistence{mockPersistence, mockTransaction}, EventPublisher: publisher}
+
+		mockTransaction.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context, application.ProductPersistenceInterface) error) error {
+				return fn(ctx, mockPersistence)
+			}).Times(2)
+		mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+
+		err := service.WithinTransaction(context.Background(), func(ctx context.Context, tx application.ProductServiceInterface) error {
+			_, err := tx.Create(ctx, "Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+			assert.Empty(t, publisher.events)
+			return err
+		})
+		assert.Nil(t, err)
+		assert.Len(t, publisher.events, 1)
+
+		err = service.WithinTransaction(context.Background(), func(ctx context.Context, tx application.ProductServiceInterface) error {
+			if _, err := tx.Create(ctx, "Product 2", application.NewMoney(1000, application.DEFAULT_CURRENCY)); err != nil {
+				return err
+			}
+			return errors.New("abort")
+		})
+		assert.EqualError(t, err, "abort")
+		assert.Len(t, publisher.events, 1)
+	})
+
+	t.Run("Error - A rolled back transaction gives the events back", func(t *testing.T) {
+		mockPersistence := mock.NewMockProductPersistenceInterface(ctrl)
+		mockTransaction := mock.NewMockProductTransactionInterface(ctrl)
+		publisher := &recordingPublisher{}
+		service := application.ProductService{ProductPersistence: transactionalPersistence{mockPersistence, mockTransaction}, EventPublisher: publisher}
+		product := application.NewProduct("Product 1", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+		product.ClearEvents()
+
+		mockTransaction.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context, application.ProductPersistenceInterface) error) error {
+				return fn(ctx, transactionalPersistence{mockPersistence, mockTransaction})
+			}).Times(2)
+		mockPersistence.EXPECT().Save(gomock.Any(), gomock.Any()).Return(product, nil).Times(2)
+
+		err := service.WithinTransaction(context.Background(), func(ctx context.Context, tx application.ProductServiceInterface) error {
+			err := tx.WithinTransaction(ctx, func(ctx context.Context, tx application.ProductServiceInterface) error {
+				_, err := tx.Enable(ctx, product)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ChangeName(ctx, product, "Product 2"); err != nil {
+				return err
+			}
+			return errors.New("abort")
+		})
+		assert.EqualError(t, err, "abort")
+		assert.Empty(t, publisher.events)
+		events := product.Events()
+		if assert.Len(t, events, 2) {
+			assert.Equal(t, application.PRODUCT_ENABLED, events[0].EventName())
+			assert.Equal(t, application.PRODUCT_RENAMED, events[1].EventName())
+		}
+	})
+}
@@ -20,7 +20,7 @@ func TestProductIsValid(t *testing.T) {
 		{
 			name: "Valid Product",
 			product: &application.Product{
-				Price:  10,
+				Price:  application.NewMoney(1000, application.DEFAULT_CURRENCY),
 				Id:     productId,
 				Name:   productName,
 				Status: application.ENABLED,
@@ -31,7 +31,7 @@ func TestProductIsValid(t *testing.T) {
 		{
 			name: "Invalid Product Status",
 			product: &application.Product{
-				Price:  10,
+				Price:  application.NewMoney(1000, application.DEFAULT_CURRENCY),
 				Id:     productId,
 				Name:   productName,
 				Status: "invalid",
@@ -42,7 +42,7 @@ func TestProductIsValid(t *testing.T) {
 		{
 			name: "Invalid Product Name",
 			product: &application.Product{
-				Price:  10,
+				Price:  application.NewMoney(1000, application.DEFAULT_CURRENCY),
 				Id:     productId,
 				Name:   "",
 				Status: application.ENABLED,
@@ -53,7 +53,7 @@ func TestProductIsValid(t *testing.T) {
 		{
 			name: "Invalid Product Id",
 			product: &application.Product{
-				Price:  10,
+				Price:  application.NewMoney(1000, application.DEFAULT_CURRENCY),
 				Id:     "invalid",
 				Name:   productName,
 				Status: application.ENABLED,
@@ -61,10 +61,21 @@ func TestProductIsValid(t *testing.T) {
 			expected: false,
 			err:      true,
 		},
+		{
+			name: "Invalid Product Currency",
+			product: &application.Product{
+				Price:  application.NewMoney(1000, "ABC"),
+				Id:     productId,
+				Name:   productName,
+				Status: application.ENABLED,
+			},
+			expected: false,
+			err:      true,
+		},
 		{
 			name: "Invalid Product Price",
 			product: &application.Product{
-				Price:  -10,
+				Price:  application.NewMoney(-1000, application.DEFAULT_CURRENCY),
 				Id:     productId,
 				Name:   productName,
 				Status: application.ENABLED,
@@ -99,7 +110,7 @@ func TestProductEnable(t *testing.T) {
 		{
 			name: "Enabled Successful",
 			product: &application.Product{
-				Price:  10,
+				Price:  application.NewMoney(1000, application.DEFAULT_CURRENCY),
 				Name:   productName,
 				Id:     productId,
 				Status: application.DISABLED,
@@ -120,7 +131,7 @@ func TestProductEnable(t *testing.T) {
 		{
 			name: "Enabled Failed - Already Enabled",
 			product: &application.Product{
-				Price:  10,
+				Price:  application.NewMoney(1000, application.DEFAULT_CURRENCY),
 				Name:   productName,
 				Id:     productId,
 				Status: application.ENABLED,
@@ -158,7 +169,7 @@ func TestProductDisable(t *testing.T) {
 				Name:   productName,
 				Id:     productId,
 				Status: application.ENABLED,
-				Price:  0,
+				Price:  application.NewMoney(0, application.DEFAULT_CURRENCY),
 			},
 			expected: application.DISABLED,
 			err:      false,
@@ -169,7 +180,7 @@ func TestProductDisable(t *testing.T) {
 				Name:   productName,
 				Id:     productId,
 				Status: application.ENABLED,
-				Price:  10,
+				Price:  application.NewMoney(1000, application.DEFAULT_CURRENCY),
 			},
 			expected: application.ENABLED,
 			err:      true,
@@ -180,7 +191,7 @@ func TestProductDisable(t *testing.T) {
 				Name:   productName,
 				Id:     productId,
 				Status: application.DISABLED,
-				Price:  0,
+				Price:  application.NewMoney(0, application.DEFAULT_CURRENCY),
 			},
 			expected: application.DISABLED,
 			err:      false,
@@ -206,8 +217,8 @@ func TestProductChangePrice(t *testing.T) {
 	tests := []struct {
 		name     string
 		product  *application.Product
-		price    float64
-		expected float64
+		price    application.Money
+		expected application.Money
 		err      bool
 	}{
 		{
@@ -216,10 +227,10 @@ func TestProductChangePrice(t *testing.T) {
 				Name:   productName,
 				Id:     productId,
 				Status: application.ENABLED,
-				Price:  10,
+				Price:  application.NewMoney(1000, application.DEFAULT_CURRENCY),
 			},
-			price:    20,
-			expected: 20,
+			price:    application.NewMoney(2000, application.DEFAULT_CURRENCY),
+			expected: application.NewMoney(2000, application.DEFAULT_CURRENCY),
 			err:      false,
 		},
 		{
@@ -228,10 +239,10 @@ func TestProductChangePrice(t *testing.T) {
 				Name:   productName,
 				Id:     productId,
 				Status: application.ENABLED,
-				Price:  10,
+				Price:  application.NewMoney(1000, application.DEFAULT_CURRENCY),
 			},
-			price:    0,
-			expected: 0,
+			price:    application.NewMoney(0, application.DEFAULT_CURRENCY),
+			expected: application.NewMoney(0, application.DEFAULT_CURRENCY),
 			err:      false,
 		},
 		{
@@ -240,10 +251,10 @@ func TestProductChangePrice(t *testing.T) {
 				Name:   productName,
 				Id:     productId,
 				Status: application.ENABLED,
-				Price:  10,
+				Price:  application.NewMoney(1000, application.DEFAULT_CURRENCY),
 			},
-			price:    -10,
-			expected: 10,
+			price:    application.NewMoney(-1000, application.DEFAULT_CURRENCY),
+			expected: application.NewMoney(1000, application.DEFAULT_CURRENCY),
 			err:      true,
 		},
 	}
@@ -268,21 +279,118 @@ func TestProductGetters(t *testing.T) {
 		Name:   productName,
 		Id:     productId,
 		Status: application.ENABLED,
-		Price:  10,
+		Price:  application.NewMoney(1000, application.DEFAULT_CURRENCY),
 	}
 
 	assert.Equal(t, productId, product.GetId())
 	assert.Equal(t, productName, product.GetName())
 	assert.Equal(t, application.ENABLED, product.GetStatus())
-	assert.Equal(t, 10.0, product.GetPrice())
+	assert.Equal(t, application.NewMoney(1000, application.DEFAULT_CURRENCY), product.GetPrice())
 }
 
 func TestProductConstructor(t *testing.T) {
 	productName := "Product 6"
-	product := application.NewProduct(productName, 10)
+	product := application.NewProduct(productName, application.NewMoney(1000, application.DEFAULT_CURRENCY))
 
 	assert.NotEmpty(t, product.GetId())
 	assert.Equal(t, productName, product.GetName())
 	assert.Equal(t, application.DISABLED, product.GetStatus())
-	assert.Equal(t, 10.0, product.GetPrice())
+	assert.Equal(t, application.NewMoney(1000, application.DEFAULT_CURRENCY), product.GetPrice())
+}
+
+func TestProductChangeName(t *testing.T) {
+	product := application.NewProduct("Product 7", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+
+	assert.Nil(t, product.ChangeName("Product 8"))
+	assert.Equal(t, "Product 8", product.GetName())
+
+	err := product.ChangeName("  ")
+	assert.Equal(t, "The name must not be empty", err.Error())
+	assert.Equal(t, "Product 8", product.GetName())
+}
+
+func TestProductArchive(t *testing.T) {
+	product := application.NewProduct("Product 8", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+	product.Enable()
+
+	assert.Nil(t, product.Archive())
+	assert.Equal(t, application.ARCHIVED, product.GetStatus())
+	assert.NotNil(t, product.GetDeletedAt())
+	valid, err := product.IsValid()
+	assert.True(t, valid)
+	assert.Nil(t, err)
+
+	assert.ErrorIs(t, product.Archive(), application.ErrInvalidTransition)
+	assert.ErrorIs(t, product.Enable(), application.ErrInvalidTransition)
+	assert.ErrorIs(t, product.Disable(), application.ErrInvalidTransition)
+	assert.Equal(t, application.ARCHIVED, product.GetStatus())
+}
+
+func TestProductRestore(t *testing.T) {
+	product := application.NewProduct("Product 9", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+
+	err := product.Restore()
+	assert.Equal(t, "Only archived products can be restored", err.Error())
+
+	product.Enable()
+	product.Archive()
+
+	assert.Nil(t, product.Restore())
+	assert.Equal(t, application.DISABLED, product.GetStatus())
+	assert.Nil(t, product.GetDeletedAt())
+}
+
+func TestProductVersion(t *testing.T) {
+	product := application.NewProduct("Product 10", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+	assert.Equal(t, 0, product.GetVersion())
+
+	product.SetVersion(2)
+	assert.Equal(t, 2, product.GetVersion())
+}
+
+func TestProductEvents(t *testing.T) {
+	product := application.NewProduct("Product 11", application.NewMoney(1000, application.DEFAULT_CURRENCY))
+
+	events := product.Events()
+	assert.Len(t, events, 1)
+	created := events[0].(application.ProductCreated)
+	assert.Equal(t, application.PRODUCT_CREATED, created.EventName())
+	assert.Equal(t, product.GetId(), created.GetProductId())
+	assert.Equal(t, "Product 11", created.Name)
+	assert.False(t, created.GetOccurredAt().IsZero())
+
+	product.ClearEvents()
+	assert.Nil(t, product.ChangePrice(application.NewMoney(1000, application.DEFAULT_CURRENCY)))
+	assert.Empty(t, product.Events())
+
+	assert.Nil(t, product.ChangePrice(application.NewMoney(1500, application.DEFAULT_CURRENCY)))
+	assert.Nil(t, product.Enable())
+	assert.Nil(t, product.Enable())
+	assert.Nil(t, product.ChangePrice(application.NewMoney(0, application.DEFAULT_CURRENCY)))
+	assert.Nil(t, product.Disable())
+
+	names := []string{}
+	for _, event := range product.Events() {
+		names = append(names, event.EventName())
+	}
+	assert.Equal(t, []string{application.PRODUCT_PRICE_CHANGED, application.PRODUCT_ENABLED, application.PRODUCT_PRICE_CHANGED, application.PRODUCT_DISABLED}, names)
+	changed := product.Events()[0].(application.ProductPriceChanged)
+	assert.Equal(t, application.NewMoney(1000, application.DEFAULT_CURRENCY), changed.OldPrice)
+	assert.Equal(t, application.NewMoney(1500, application.DEFAULT_CURRENCY), changed.NewPrice)
+
+	product.ClearEvents()
+	assert.Nil(t, product.ChangeName("Product 11"))
+	assert.Empty(t, product.Events())
+	assert.Nil(t, product.ChangeName("Product 12"))
+	assert.Nil(t, product.Archive())
+	assert.Nil(t, product.Restore())
+
+	names = []string{}
+	for _, event := range product.Events() {
+		names = append(names, event.EventName())
+	}
+	assert.Equal(t, []string{application.PRODUCT_RENAMED, application.PRODUCT_ARCHIVED, application.PRODUCT_RESTORED}, names)
+	renamed := product.Events()[0].(application.ProductRenamed)
+	assert.Equal(t, "Product 11", renamed.OldName)
+	assert.Equal(t, "Product 12", renamed.NewName)
 }
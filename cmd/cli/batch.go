@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/cli"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/db"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+)
+
+func runBatch(action string, args []string, dsn, storage, output string, stdout, stderr io.Writer) int {
+	command := flag.NewFlagSet("product "+action, flag.ContinueOnError)
+	command.SetOutput(stderr)
+	command.StringVar(&dsn, "db", dsn, "path to the SQLite database file")
+	outputFlag(command, &output)
+	actor := actorFlag(command)
+	bestEffort := command.Bool("best-effort", false, "keep the items that succeed instead of rolling back the whole batch")
+
+	var file, ids, currency string
+	if action == "create-many" {
+		command.StringVar(&file, "file", "", `CSV file with "name,price[,currency]" rows, or "-" for stdin`)
+		command.StringVar(&currency, "currency", application.DEFAULT_CURRENCY, "ISO 4217 currency of rows without one")
+	} else {
+		command.StringVar(&ids, "ids", "", "comma separated ids of the products")
+	}
+
+	if err := command.Parse(args); err != nil {
+		return usageExitCode(err)
+	}
+	if !checkOutput(command, output, stderr) {
+		return exitUsage
+	}
+	if action == "create-many" && file == "" {
+		fmt.Fprintln(stderr, "flag --file is required")
+		command.Usage()
+		return exitUsage
+	}
+	if action != "create-many" && ids == "" {
+		fmt.Fprintln(stderr, "flag --ids is required")
+		command.Usage()
+		return exitUsage
+	}
+
+	var inputs []application.ProductInput
+	if action == "create-many" {
+		var err error
+		inputs, err = readProductInputs(file, currency)
+		if err != nil {
+			fmt.Fprintln(stderr, cli.FormatError(err, output))
+			var pathErr *fs.PathError
+			if errors.As(err, &pathErr) {
+				return exitUsage
+			}
+			return exitValidation
+		}
+	}
+
+	mode := application.BATCH_ALL_OR_NOTHING
+	if *bestEffort {
+		mode = application.BATCH_BEST_EFFORT
+	}
+
+	conn, err := openDb(dsn)
+	if err != nil {
+		fmt.Fprintln(stderr, cli.FormatError(err, output))
+		return exitStorage
+	}
+	defer conn.Close()
+
+	if err := db.Migrate(conn); err != nil {
+		fmt.Fprintln(stderr, cli.FormatError(err, output))
+		return exitStorage
+	}
+
+	ctx, stop := signal.NotifyContext(application.WithActor(context.Background(), *actor), os.Interrupt)
+	defer stop()
+
+	persistence := newPersistence(conn, storage)
+	defer persistence.Close()
+
+	service := application.NewProductService(persistence)
+	result, err := cli.RunBatch(ctx, service, action, strings.Split(ids, ","), inputs, mode, output)
+	if result != "" {
+		fmt.Fprintln(stdout, result)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, cli.FormatError(err, output))
+		return exitCode(err)
+	}
+	return exitOK
+}
+
+func readProductInputs(file, currency string) ([]application.ProductInput, error) {
+	var source io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		source = f
+	}
+
+	reader := csv.NewReader(source)
+	reader.FieldsPerRecord = -1
+	var inputs []application.ProductInput
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return inputs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(record) < 2 || len(record) > 3 {
+			return nil, fmt.Errorf("line %d: expected name,price[,currency]", line)
+		}
+		rowCurrency := currency
+		if len(record) == 3 {
+			rowCurrency = record[2]
+		}
+		price, err := application.ParseMoney(record[1], rowCurrency)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		inputs = append(inputs, application.ProductInput{Name: record[0], Price: price})
+	}
+}
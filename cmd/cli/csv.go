@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/cli"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/db"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+)
+
+func runCsv(action string, args []string, dsn, storage, output string, stdout, stderr io.Writer) int {
+	command := flag.NewFlagSet("product "+action, flag.ContinueOnError)
+	command.SetOutput(stderr)
+	command.StringVar(&dsn, "db", dsn, "path to the SQLite database file")
+	outputFlag(command, &output)
+	actor := actorFlag(command)
+
+	var file string
+	var dryRun bool
+	var query application.ProductQuery
+	if action == "import" {
+		command.StringVar(&file, "file", "", `CSV file with id,name,price,status[,currency] columns, or "-" for stdin`)
+		command.BoolVar(&dryRun, "dry-run", false, "validate every row without saving anything")
+	} else {
+		command.StringVar(&file, "file", "-", `CSV file to write, or "-" for stdout`)
+		command.StringVar(&query.Status, "status", "", "only export products with this status (enabled, disabled or archived)")
+		command.StringVar(&query.Name, "name", "", "only export products whose name contains this text")
+	}
+
+	if err := command.Parse(args); err != nil {
+		return usageExitCode(err)
+	}
+	if !checkOutput(command, output, stderr) {
+		return exitUsage
+	}
+	if file == "" {
+		fmt.Fprintln(stderr, "flag --file is required")
+		command.Usage()
+		return exitUsage
+	}
+
+	conn, err := openDb(dsn)
+	if err != nil {
+		fmt.Fprintln(stderr, cli.FormatError(err, output))
+		return exitStorage
+	}
+	defer conn.Close()
+
+	if err := db.Migrate(conn); err != nil {
+		fmt.Fprintln(stderr, cli.FormatError(err, output))
+		return exitStorage
+	}
+
+	ctx, stop := signal.NotifyContext(application.WithActor(context.Background(), *actor), os.Interrupt)
+	defer stop()
+
+	persistence := newPersistence(conn, storage)
+	defer persistence.Close()
+
+	service := application.NewProductService(persistence)
+	if action == "import" {
+		return runImport(ctx, service, file, dryRun, output, stdout, stderr)
+	}
+	return runExport(ctx, service, file, query, output, stdout, stderr)
+}
+
+func runImport(ctx context.Context, service application.ProductServiceInterface, file string, dryRun bool, output string, stdout, stderr io.Writer) int {
+	var source io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			fmt.Fprintln(stderr, cli.FormatError(err, output))
+			return exitUsage
+		}
+		defer f.Close()
+		source = f
+	}
+
+	result, err := cli.Import(ctx, service, source, dryRun, output)
+	if result != "" {
+		fmt.Fprintln(stdout, result)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, cli.FormatError(err, output))
+		return exitCode(err)
+	}
+	return exitOK
+}
+
+func runExport(ctx context.Context, service application.ProductServiceInterface, file string, query application.ProductQuery, output string, stdout, stderr io.Writer) int {
+	target := stdout
+	if file != "-" {
+		f, err := os.Create(file)
+		if err != nil {
+			fmt.Fprintln(stderr, cli.FormatError(err, output))
+			return exitUsage
+		}
+		defer f.Close()
+		target = f
+	}
+
+	if err := service.Export(ctx, target, query); err != nil {
+		fmt.Fprintln(stderr, cli.FormatError(err, output))
+		return exitCode(err)
+	}
+	return exitOK
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/cli"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/db"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+)
+
+const (
+	exitOK = iota
+	exitStorage
+	exitUsage
+	exitValidation
+	exitNotFound
+	exitConflict
+)
+
+const (
+	STORAGE_SQL    = "sql"
+	STORAGE_EVENTS = "events"
+)
+
+const usage = `Usage: cli [--db path] [--output format] <command> <subcommand> [flags]
+
+Commands:
+  product create --name <name> --price <price> [--currency <code>]
+                                                 Create a disabled product
+  product get --id <id>                          Show a product
+  product list [--status <status>] [--name <text>] [--min-price <price>]
+               [--max-price <price>] [--currency <code>] [--sort name|price] [--desc]
+               [--limit <n>] [--cursor <cursor>]     List products
+  product enable --id <id>                       Enable a product
+  product disable --id <id>                      Disable a product
+  product create-many --file <path|-> [--currency <code>] [--best-effort]
+                                                 Create products from "name,price[,currency]" CSV rows
+  product enable-many --ids <id,id,...> [--best-effort]
+                                                 Enable several products in one transaction
+  product disable-many --ids <id,id,...> [--best-effort]
+                                                 Disable several products in one transaction
+  product rename --id <id> --name <name>         Rename a product
+  product set-price --id <id> --price <price> [--currency <code>]
+                                                 Change the price of a product
+  product import --file <path|-> [--dry-run]
+                                                 Create or update products from id,name,price,status[,currency] CSV
+  product export [--file <path|->] [--status <status>] [--name <text>]
+                                                 Write products as CSV, page by page
+  product archive --id <id>                      Archive a product, hiding it from reads
+  product restore --id <id>                      Restore an archived product as disabled
+  product purge --id <id>                        Permanently delete a product
+  product history --id <id>                      Show every recorded change of a product
+  tui                                            Browse, search and edit products interactively
+  migrate up                                     Apply every pending migration
+  migrate down                                   Roll back the last applied migration
+  migrate status                                 Show which migrations are applied
+
+Global flags:
+  --db <path>        Path to the SQLite database file (default "sqlite.db")
+  --storage <kind>   "sql" stores the current state of each product, "events"
+                     its stream of events (default "sql")
+  --output <format>  "text", "json", "yaml" or "table"; json and yaml also
+                     write errors to stderr as {"error": {"code", "message",
+                     "fields"}} (default "text")
+
+Product flags:
+  --actor <name>  Who is making the change, recorded in the product history (default $USER)
+
+Exit codes:
+  0  success
+  1  storage error
+  2  usage error
+  3  validation error
+  4  product not found
+  5  product was modified concurrently, retry the command
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	global := flag.NewFlagSet("cli", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	global.Usage = func() { fmt.Fprint(stderr, usage) }
+	dsn := global.String("db", "sqlite.db", "path to the SQLite database file")
+	storage := global.String("storage", STORAGE_SQL, "how products are stored: sql or events")
+	output := global.String("output", cli.FORMAT_TEXT, "output format: text, json, yaml or table")
+	if err := global.Parse(args); err != nil {
+		return usageExitCode(err)
+	}
+	if *storage != STORAGE_SQL && *storage != STORAGE_EVENTS {
+		fmt.Fprintf(stderr, "unknown storage %q\n\n", *storage)
+		global.Usage()
+		return exitUsage
+	}
+	if !cli.ValidFormat(*output) {
+		fmt.Fprintf(stderr, "unknown output format %q\n\n", *output)
+		global.Usage()
+		return exitUsage
+	}
+
+	args = global.Args()
+	if len(args) == 0 || args[0] == "help" {
+		global.Usage()
+		return exitUsage
+	}
+	if args[0] == "tui" {
+		return runTui(args[1:], *dsn, *storage, stdout, stderr)
+	}
+	if len(args) < 2 {
+		global.Usage()
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "product":
+		return runProduct(args[1], args[2:], *dsn, *storage, *output, stdout, stderr)
+	case "migrate":
+		return runMigrate(args[1], args[2:], *dsn, stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		global.Usage()
+		return exitUsage
+	}
+}
+
+func openDb(dsn string) (*sql.DB, error) {
+	conn, err := db.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+type productPersistence interface {
+	application.ProductPersistenceInterface
+	Close() error
+}
+
+func newPersistence(conn *sql.DB, storage string) productPersistence {
+	if storage == STORAGE_EVENTS {
+		return db.NewProductEventStore(conn).WithOutbox()
+	}
+	return db.NewProductDb(conn).WithOutbox()
+}
+
+func usageExitCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	return exitUsage
+}
+
+// outputFlag lets a subcommand override the global --output flag.
+func outputFlag(command *flag.FlagSet, output *string) {
+	command.StringVar(output, "output", *output, "output format: text, json, yaml or table")
+}
+
+func checkOutput(command *flag.FlagSet, output string, stderr io.Writer) bool {
+	if cli.ValidFormat(output) {
+		return true
+	}
+	fmt.Fprintf(stderr, "unknown output format %q\n", output)
+	command.Usage()
+	return false
+}
+
+func exitCode(err error) int {
+	var validationErr *application.ValidationError
+	switch {
+	case errors.Is(err, application.ErrProductNotFound):
+		return exitNotFound
+	case errors.Is(err, application.ErrConcurrentModification):
+		return exitConflict
+	case errors.As(err, &validationErr), errors.Is(err, application.ErrInvalidPrice), errors.Is(err, application.ErrInvalidTransition):
+		return exitValidation
+	default:
+		return exitStorage
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+	"github.com/stretchr/testify/assert"
+)
+
+func runCli(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, strings.TrimSpace(stdout.String()), strings.TrimSpace(stderr.String())
+}
+
+func createProduct(t *testing.T, dsn, name, price string) string {
+	t.Helper()
+	code, stdout, stderr := runCli(t, "--db", dsn, "--output", "json", "product", "create", "--name", name, "--price", price)
+	if !assert.Equal(t, exitOK, code, stderr) {
+		t.FailNow()
+	}
+	var product struct {
+		Id string `json:"id"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(stdout), &product))
+	return product.Id
+}
+
+func TestRun(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "sqlite.db")
+
+	id := createProduct(t, dsn, "Product 1", "10")
+	free := createProduct(t, dsn, "Free", "0")
+	missing := filepath.Join(t.TempDir(), "missing.csv")
+
+	tests := []struct {
+		testName string
+		args     []string
+		code     int
+		stdout   string
+		stderr   string
+	}{
+		{
+			testName: "Success - Help",
+			args:     []string{"-h"},
+			code:     exitOK,
+			stderr:   "Usage: cli",
+		},
+		{
+			testName: "Success - Get a product",
+			args:     []string{"--db", dsn, "product", "get", "--id", id},
+			code:     exitOK,
+			stdout:   "Name: Product 1",
+		},
+		{
+			testName: "Success - Subcommand flags override the global ones",
+			args:     []string{"product", "list", "--db", dsn, "--output", "table"},
+			code:     exitOK,
+			stdout:   "10.00 BRL",
+		},
+		{
+			testName: "Error - No command",
+			args:     []string{"--db", dsn},
+			code:     exitUsage,
+			stderr:   "Usage: cli",
+		},
+		{
+			testName: "Error - Unknown command",
+			args:     []string{"--db", dsn, "order", "list"},
+			code:     exitUsage,
+			stderr:   `unknown command "order"`,
+		},
+		{
+			testName: "Error - Unknown subcommand",
+			args:     []string{"--db", dsn, "product", "sell"},
+			code:     exitUsage,
+			stderr:   `unknown subcommand "sell"`,
+		},
+		{
+			testName: "Error - Unknown flag",
+			args:     []string{"--db", dsn, "product", "get", "--sku", "1"},
+			code:     exitUsage,
+			stderr:   "flag provided but not defined: -sku",
+		},
+		{
+			testName: "Error - Unknown storage",
+			args:     []string{"--storage", "files", "product", "list"},
+			code:     exitUsage,
+			stderr:   `unknown storage "files"`,
+		},
+		{
+			testName: "Error - Unknown output format",
+			args:     []string{"--db", dsn, "product", "list", "--output", "xml"},
+			code:     exitUsage,
+			stderr:   `unknown output format "xml"`,
+		},
+		{
+			testName: "Error - The id is required",
+			args:     []string{"--db", dsn, "product", "enable"},
+			code:     exitUsage,
+			stderr:   "flag --id is required",
+		},
+		{
+			testName: "Error - The price is required",
+			args:     []string{"--db", dsn, "product", "set-price", "--id", id},
+			code:     exitUsage,
+			stderr:   "flag --price is required",
+		},
+		{
+			testName: "Error - Invalid price",
+			args:     []string{"--db", dsn, "product", "create", "--name", "Product 2", "--price", "ten"},
+			code:     exitValidation,
+			stderr:   `The price "ten" is not a valid amount`,
+		},
+		{
+			testName: "Error - Invalid transition",
+			args:     []string{"--db", dsn, "--output", "json", "product", "enable", "--id", free},
+			code:     exitValidation,
+			stderr:   `"code": "INVALID_TRANSITION"`,
+		},
+		{
+			testName: "Error - Unreadable file of create-many",
+			args:     []string{"--db", dsn, "--output", "json", "product", "create-many", "--file", missing},
+			code:     exitUsage,
+			stderr:   `"code": "FILE_ERROR"`,
+		},
+		{
+			testName: "Error - Unreadable file of import",
+			args:     []string{"--db", dsn, "--output", "json", "product", "import", "--file", missing},
+			code:     exitUsage,
+			stderr:   `"code": "FILE_ERROR"`,
+		},
+		{
+			testName: "Error - Unwritable file of export",
+			args:     []string{"--db", dsn, "--output", "json", "product", "export", "--file", filepath.Join(missing, "products.csv")},
+			code:     exitUsage,
+			stderr:   `"code": "FILE_ERROR"`,
+		},
+		{
+			testName: "Error - Product not found",
+			args:     []string{"--db", dsn, "product", "get", "--id", "missing"},
+			code:     exitNotFound,
+			stderr:   "Product not found: missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			code, stdout, stderr := runCli(t, tt.args...)
+			assert.Equal(t, tt.code, code)
+			assert.Contains(t, stdout, tt.stdout)
+			assert.Contains(t, stderr, tt.stderr)
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		testName string
+		err      error
+		code     int
+	}{
+		{"Success - Not found", application.ErrProductNotFound, exitNotFound},
+		{"Success - Concurrent modification", application.ErrConcurrentModification, exitConflict},
+		{"Success - Validation", application.NewValidationError("name", "The name must not be empty", nil), exitValidation},
+		{"Success - Invalid price", application.ErrInvalidPrice, exitValidation},
+		{"Success - Invalid transition", &application.TransitionError{From: application.ENABLED, To: application.DISABLED, Reason: "The price must be zero to disable the product"}, exitValidation},
+		{"Success - Storage", errors.New("disk I/O error"), exitStorage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			assert.Equal(t, tt.code, exitCode(tt.err))
+		})
+	}
+}
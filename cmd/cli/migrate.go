@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/db"
+)
+
+func runMigrate(action string, args []string, dsn string, stdout, stderr io.Writer) int {
+	command := flag.NewFlagSet("migrate "+action, flag.ContinueOnError)
+	command.SetOutput(stderr)
+	command.StringVar(&dsn, "db", dsn, "path to the SQLite database file")
+
+	if action != "up" && action != "down" && action != "status" {
+		fmt.Fprintf(stderr, "unknown subcommand %q\n\n%s", action, usage)
+		return exitUsage
+	}
+	if err := command.Parse(args); err != nil {
+		return usageExitCode(err)
+	}
+
+	conn, err := openDb(dsn)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitStorage
+	}
+	defer conn.Close()
+
+	migrator, err := db.NewMigrator(conn)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitStorage
+	}
+
+	switch action {
+	case "up":
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			fmt.Fprintf(stdout, "Migration %04d_%s has been applied\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitStorage
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(stdout, "No pending migrations")
+		}
+	case "down":
+		migration, err := migrator.Down()
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitStorage
+		}
+		if migration == nil {
+			fmt.Fprintln(stdout, "No applied migrations")
+			return exitOK
+		}
+		fmt.Fprintf(stdout, "Migration %04d_%s has been rolled back\n", migration.Version, migration.Name)
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitStorage
+		}
+		writer := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "VERSION\tNAME\tAPPLIED AT")
+		for _, migration := range status {
+			appliedAt := "pending"
+			if migration.AppliedAt != nil {
+				appliedAt = migration.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(writer, "%04d\t%s\t%s\n", migration.Version, migration.Name, appliedAt)
+		}
+		writer.Flush()
+	}
+
+	return exitOK
+}
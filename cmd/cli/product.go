@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/cli"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/db"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+)
+
+func runProduct(action string, args []string, dsn, storage, output string, stdout, stderr io.Writer) int {
+	switch action {
+	case "create-many", "enable-many", "disable-many":
+		return runBatch(action, args, dsn, storage, output, stdout, stderr)
+	case "import", "export":
+		return runCsv(action, args, dsn, storage, output, stdout, stderr)
+	}
+
+	command := flag.NewFlagSet("product "+action, flag.ContinueOnError)
+	command.SetOutput(stderr)
+	command.StringVar(&dsn, "db", dsn, "path to the SQLite database file")
+	outputFlag(command, &output)
+	actor := actorFlag(command)
+
+	var productId, productName, productPrice, minPrice, maxPrice string
+	var currency string
+	var query application.ProductQuery
+	switch action {
+	case "create":
+		command.StringVar(&productName, "name", "", "name of the product")
+		command.StringVar(&productPrice, "price", "0", "price of the product, e.g. 19.99")
+		command.StringVar(&currency, "currency", application.DEFAULT_CURRENCY, "ISO 4217 currency of the price")
+	case "get", "enable", "disable", "archive", "restore", "purge", "history":
+		command.StringVar(&productId, "id", "", "id of the product")
+	case "rename":
+		command.StringVar(&productId, "id", "", "id of the product")
+		command.StringVar(&productName, "name", "", "new name of the product")
+	case "set-price":
+		command.StringVar(&productId, "id", "", "id of the product")
+		command.StringVar(&productPrice, "price", "", "new price of the product, e.g. 19.99")
+		command.StringVar(&currency, "currency", application.DEFAULT_CURRENCY, "ISO 4217 currency of the price")
+	case "list":
+		command.StringVar(&query.Status, "status", "", "only list products with this status (enabled, disabled or archived)")
+		command.StringVar(&query.Name, "name", "", "only list products whose name contains this text")
+		command.StringVar(&minPrice, "min-price", "", "only list products with at least this price")
+		command.StringVar(&maxPrice, "max-price", "", "only list products with at most this price")
+		command.StringVar(&currency, "currency", application.DEFAULT_CURRENCY, "ISO 4217 currency of the price filters")
+		command.StringVar(&query.SortBy, "sort", application.SORT_BY_NAME, "sort products by name or price")
+		command.BoolVar(&query.Desc, "desc", false, "sort products in descending order")
+		command.IntVar(&query.Limit, "limit", application.DEFAULT_PAGE_SIZE, "maximum number of products per page")
+		command.StringVar(&query.Cursor, "cursor", "", "cursor returned by the previous page")
+	default:
+		fmt.Fprintf(stderr, "unknown subcommand %q\n\n%s", action, usage)
+		return exitUsage
+	}
+
+	if err := command.Parse(args); err != nil {
+		return usageExitCode(err)
+	}
+	if !checkOutput(command, output, stderr) {
+		return exitUsage
+	}
+	if action != "create" && action != "list" && productId == "" {
+		fmt.Fprintln(stderr, "flag --id is required")
+		command.Usage()
+		return exitUsage
+	}
+
+	if action == "set-price" && productPrice == "" {
+		fmt.Fprintln(stderr, "flag --price is required")
+		command.Usage()
+		return exitUsage
+	}
+
+	price, err := parsePrices(currency, productPrice, minPrice, maxPrice, &query)
+	if err != nil {
+		fmt.Fprintln(stderr, cli.FormatError(err, output))
+		return exitValidation
+	}
+
+	conn, err := openDb(dsn)
+	if err != nil {
+		fmt.Fprintln(stderr, cli.FormatError(err, output))
+		return exitStorage
+	}
+	defer conn.Close()
+
+	if err := db.Migrate(conn); err != nil {
+		fmt.Fprintln(stderr, cli.FormatError(err, output))
+		return exitStorage
+	}
+
+	ctx, stop := signal.NotifyContext(application.WithActor(context.Background(), *actor), os.Interrupt)
+	defer stop()
+
+	persistence := newPersistence(conn, storage)
+	defer persistence.Close()
+
+	service := application.NewProductService(persistence)
+	var result string
+	if action == "list" {
+		result, err = cli.List(ctx, service, query, output)
+	} else {
+		result, err = cli.Run(ctx, service, action, productId, productName, price, output)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, cli.FormatError(err, output))
+		return exitCode(err)
+	}
+
+	fmt.Fprintln(stdout, result)
+	return exitOK
+}
+
+func parsePrices(currency, productPrice, minPrice, maxPrice string, query *application.ProductQuery) (application.Money, error) {
+	var price application.Money
+	if productPrice != "" {
+		parsed, err := application.ParseMoney(productPrice, currency)
+		if err != nil {
+			return price, err
+		}
+		price = parsed
+	}
+	if minPrice != "" {
+		parsed, err := application.ParseMoney(minPrice, currency)
+		if err != nil {
+			return price, err
+		}
+		query.MinPrice = &parsed
+	}
+	if maxPrice != "" {
+		parsed, err := application.ParseMoney(maxPrice, currency)
+		if err != nil {
+			return price, err
+		}
+		query.MaxPrice = &parsed
+	}
+	return price, nil
+}
+
+func actorFlag(command *flag.FlagSet) *string {
+	actor := os.Getenv("USER")
+	if actor == "" {
+		actor = "cli"
+	}
+	return command.String("actor", actor, "who is making the change, recorded in the product history")
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/db"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/tui"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+)
+
+func runTui(args []string, dsn, storage string, stdout, stderr io.Writer) int {
+	command := flag.NewFlagSet("tui", flag.ContinueOnError)
+	command.SetOutput(stderr)
+	command.StringVar(&dsn, "db", dsn, "path to the SQLite database file")
+	actor := actorFlag(command)
+	if err := command.Parse(args); err != nil {
+		return usageExitCode(err)
+	}
+
+	conn, err := openDb(dsn)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitStorage
+	}
+	defer conn.Close()
+
+	if err := db.Migrate(conn); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitStorage
+	}
+
+	ctx, stop := signal.NotifyContext(application.WithActor(context.Background(), *actor), os.Interrupt)
+	defer stop()
+
+	persistence := newPersistence(conn, storage)
+	defer persistence.Close()
+
+	if err := tui.Run(ctx, application.NewProductService(persistence), os.Stdin, stdout); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitStorage
+	}
+	return exitOK
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/netip"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/db"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/eventbus"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/graphql"
+	productgrpc "github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/grpc"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/memory"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/adapters/web"
+	"github.com/sousapedro11/fc-arquitetura-hexagonal/application"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address the HTTP server listens on")
+	grpcAddr := flag.String("grpc-addr", ":9090", "address the gRPC server listens on, empty to disable it")
+	grpcReflection := flag.Bool("grpc-reflection", false, "enable gRPC server reflection")
+	graphqlMaxDepth := flag.Int("graphql-max-depth", graphql.DEFAULT_MAX_DEPTH, "deepest GraphQL query the server accepts")
+	dsn := flag.String("db", "sqlite.db", "path to the SQLite database file")
+	storage := flag.String("storage", "sql", "how products are stored: sql, events or memory")
+	logEvents := flag.Bool("log-events", false, "log every product domain event")
+	trustedProxies := flag.String("trusted-proxies", "127.0.0.1/32,::1/128", "comma-separated networks allowed to name the actor of a change, empty to trust none")
+	flag.Parse()
+
+	proxies, err := parsePrefixes(*trustedProxies)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	bus := eventbus.NewBus()
+	if *logEvents {
+		bus.Subscribe(func(ctx context.Context, event application.DomainEvent) error {
+			log.Printf("event %s for product %s", event.EventName(), event.GetProductId())
+			return nil
+		})
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	productService := application.NewProductService(nil)
+	if *storage == "memory" {
+		// Nothing outlives the process, so events go straight to the bus
+		// instead of through an outbox.
+		productService.ProductPersistence = memory.NewProductMemory()
+		productService.EventPublisher = bus
+	} else {
+		persistence, closeDb, err := openDb(ctx, *dsn, *storage, bus)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer closeDb()
+		productService.ProductPersistence = persistence
+	}
+	server := web.NewWebserver(productService)
+	server.TrustedProxies = proxies
+	graphqlServer := graphql.NewServer(productService)
+	graphqlServer.MaxDepth = *graphqlMaxDepth
+	server.GraphQL = graphqlServer.Handler()
+
+	servers := 1
+	errs := make(chan error, 2)
+	log.Printf("Webserver has been started on %s", *addr)
+	go func() {
+		errs <- server.Serve(ctx, *addr)
+	}()
+
+	if *grpcAddr != "" {
+		grpcServer := productgrpc.NewProductServer(productService)
+		grpcServer.Reflection = *grpcReflection
+		grpcServer.TrustedProxies = proxies
+		servers++
+		log.Printf("gRPC server has been started on %s", *grpcAddr)
+		go func() {
+			errs <- grpcServer.Serve(ctx, *grpcAddr)
+		}()
+	}
+
+	for range servers {
+		if err := <-errs; err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func parsePrefixes(value string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", field, err)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, nil
+}
+
+// openDb migrates the SQLite database and starts relaying its outbox to the
+// bus until ctx is done.
+func openDb(ctx context.Context, dsn, storage string, bus application.EventPublisherInterface) (application.ProductPersistenceInterface, func(), error) {
+	conn, err := db.Open(dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := db.Migrate(conn); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	var persistence interface {
+		application.ProductPersistenceInterface
+		Close() error
+	}
+	switch storage {
+	case "sql":
+		persistence = db.NewProductDb(conn).WithOutbox()
+	case "events":
+		persistence = db.NewProductEventStore(conn).WithOutbox()
+	default:
+		conn.Close()
+		return nil, nil, fmt.Errorf("unknown storage %q", storage)
+	}
+
+	relay := db.NewOutboxRelay(conn, bus)
+	go relay.Run(ctx)
+
+	return persistence, func() {
+		persistence.Close()
+		conn.Close()
+	}, nil
+}